@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/major1ink/simple-notification-telegram/internal/app"
+	"github.com/major1ink/simple-notification-telegram/internal/config"
 )
 
 //go:embed VERSION
@@ -24,8 +25,14 @@ func main() {
 		}
 	}
 
-	var configPath string
+	var (
+		configPath     string
+		validateConfig bool
+		printConfig    bool
+	)
 	flag.StringVar(&configPath, "configPath", "", "path to config file")
+	flag.BoolVar(&validateConfig, "validate-config", false, "validate config and exit")
+	flag.BoolVar(&printConfig, "print-config", false, "print resolved config with secrets redacted and exit")
 
 	flag.Parse()
 
@@ -36,6 +43,33 @@ func main() {
 		}
 	}
 
+	if validateConfig {
+		err := app.ValidateConfig()
+		for _, warning := range config.Warnings() {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println("Config is valid")
+		os.Exit(0)
+	}
+
+	if printConfig {
+		if err := app.ValidateConfig(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		dump, err := config.Dump()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print(dump)
+		os.Exit(0)
+	}
+
 	ctx := context.Background()
 	a, err := app.New(ctx)
 	if err != nil {
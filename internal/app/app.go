@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"syscall"
 	"time"
@@ -18,6 +19,7 @@ type App struct {
 	diContainer *diContainer
 	logger      *zap.Logger
 	closer      *closer.Closer
+	httpServer  *http.Server
 }
 
 func New(ctx context.Context) (*App, error) {
@@ -32,7 +34,17 @@ func New(ctx context.Context) (*App, error) {
 }
 
 func (a *App) Run(ctx context.Context) error {
-	errCh := make(chan error)
+	errCh := make(chan error, 2)
+
+	if a.httpServer != nil {
+		go func() {
+			if err := a.runHTTPServer(); err != nil {
+				errCh <- errors.Errorf("http server crashed: %v", err)
+			}
+		}()
+	}
+
+	go a.runConfigReloader(ctx)
 
 	go func() {
 		if err := a.runAssembledConsumer(ctx); err != nil {
@@ -48,8 +60,6 @@ func (a *App) Run(ctx context.Context) error {
 		a.logger.Error("Component crashed, shutting down", zap.Error(err))
 		return a.gracefulShutdown(ctx)
 	}
-
-	return nil
 }
 
 func (a *App) initDeps(ctx context.Context) error {
@@ -58,6 +68,7 @@ func (a *App) initDeps(ctx context.Context) error {
 		a.initCloser,
 		a.initLogger,
 		a.initDI,
+		a.initHTTPServer,
 	}
 
 	for _, f := range inits {
@@ -71,11 +82,29 @@ func (a *App) initDeps(ctx context.Context) error {
 }
 
 func (a *App) initConfig(_ context.Context) error {
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = "config.yaml"
+	return ValidateConfig()
+}
+
+// ValidateConfig загружает конфигурацию и проверяет её, возвращая все найденные ошибки
+func ValidateConfig() error {
+	if err := config.Load(configPath()); err != nil {
+		return err
 	}
-	return config.Load(configPath)
+
+	return config.Validate()
+}
+
+// configPath возвращает путь к файлу конфигурации. Явно указанный файл обязателен,
+// файл по умолчанию — нет: конфигурация может полностью задаваться переменными окружения
+func configPath() string {
+	path := os.Getenv("CONFIG_PATH")
+	if path == "" {
+		if _, err := os.Stat("config.yaml"); err == nil {
+			path = "config.yaml"
+		}
+	}
+
+	return path
 }
 
 func (a *App) initDI(_ context.Context) error {
@@ -85,6 +114,35 @@ func (a *App) initDI(_ context.Context) error {
 	return nil
 }
 
+func (a *App) initHTTPServer(_ context.Context) error {
+	if !config.AppConfig().HTTPServer.GetEnabled() {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", a.diContainer.Health().LivenessHandler())
+	mux.Handle("/readyz", a.diContainer.Health().ReadinessHandler())
+	mux.Handle("/metrics", a.diContainer.Metrics().Handler())
+	if config.AppConfig().Journal.GetAdminEnabled() {
+		mux.Handle("/journal", a.diContainer.Journal().Handler(config.AppConfig().Journal.GetAdminToken()))
+	}
+	if config.AppConfig().Maintenance.GetAdminEnabled() {
+		mux.Handle("/maintenance", a.diContainer.Maintenance().Handler(config.AppConfig().Maintenance.GetAdminToken()))
+	}
+
+	a.httpServer = &http.Server{
+		Addr:              config.AppConfig().HTTPServer.GetAddress(),
+		Handler:           mux,
+		ReadHeaderTimeout: config.AppConfig().HTTPServer.GetReadHeaderTimeout(),
+	}
+
+	a.closer.AddNamed("HTTP server", func(ctx context.Context) error {
+		return a.httpServer.Shutdown(ctx)
+	})
+
+	return nil
+}
+
 func (a *App) initLogger(ctx context.Context) error {
 
 	l, err := logger.NewLog("simple-notification-telegram.log")
@@ -101,10 +159,23 @@ func (a *App) initLogger(ctx context.Context) error {
 		return nil
 	})
 
+	// Секреты в выводе заменены на ***
+	if dump, err := config.Dump(); err == nil {
+		a.logger.Debug("Configuration loaded", zap.String("config", dump))
+	}
+	a.logConfigWarnings()
+
 	return nil
 
 }
 
+// logConfigWarnings выводит предупреждения проверки конфигурации
+func (a *App) logConfigWarnings() {
+	for _, warning := range config.Warnings() {
+		a.logger.Warn("Suspicious configuration value", zap.String("problem", warning.String()))
+	}
+}
+
 func (a *App) initCloser(_ context.Context) error {
 	a.closer = closer.NewWithLogger(zap.NewNop(), syscall.SIGINT, syscall.SIGTERM)
 	return nil
@@ -121,6 +192,17 @@ func (a *App) runAssembledConsumer(ctx context.Context) error {
 	return nil
 }
 
+func (a *App) runHTTPServer() error {
+	a.logger.Info("🌐 HTTP server running", zap.String("address", a.httpServer.Addr))
+
+	err := a.httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
 func (a *App) gracefulShutdown(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
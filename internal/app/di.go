@@ -8,36 +8,73 @@ import (
 	"github.com/go-telegram/bot"
 	"go.uber.org/zap"
 
+	"github.com/major1ink/simple-notification-telegram/internal/binding"
 	httpClient "github.com/major1ink/simple-notification-telegram/internal/client/http"
 	telegramClient "github.com/major1ink/simple-notification-telegram/internal/client/http/telegram"
 	"github.com/major1ink/simple-notification-telegram/internal/config"
 	kafkaConverter "github.com/major1ink/simple-notification-telegram/internal/converter/kafka"
 	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+	"github.com/major1ink/simple-notification-telegram/internal/correlation"
+	"github.com/major1ink/simple-notification-telegram/internal/dedup"
+	"github.com/major1ink/simple-notification-telegram/internal/eventkey"
+	"github.com/major1ink/simple-notification-telegram/internal/health"
+	"github.com/major1ink/simple-notification-telegram/internal/held"
+	"github.com/major1ink/simple-notification-telegram/internal/journal"
+	"github.com/major1ink/simple-notification-telegram/internal/maintenance"
+	"github.com/major1ink/simple-notification-telegram/internal/metrics"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
 	"github.com/major1ink/simple-notification-telegram/internal/service"
 	assembledConsumer "github.com/major1ink/simple-notification-telegram/internal/service/consumer"
+	dedupService "github.com/major1ink/simple-notification-telegram/internal/service/dedup"
+	maintenanceService "github.com/major1ink/simple-notification-telegram/internal/service/maintenance"
 	telegramService "github.com/major1ink/simple-notification-telegram/internal/service/telegram"
 	"github.com/major1ink/simple-notification-telegram/pkg/closer"
 	wrappedKafka "github.com/major1ink/simple-notification-telegram/pkg/kafka"
 	wrappedKafkaConsumer "github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+	wrappedKafkaProducer "github.com/major1ink/simple-notification-telegram/pkg/kafka/producer"
 )
 
 type diContainer struct {
 	assembleConsumerService service.ConsumerService
 	telegramService         service.TelegramService
 
+	dedupStore       dedup.Store
+	journal          *journal.Journal
+	correlationStore correlation.Store
+	heldStore        *held.Store
+	maintenance      *maintenance.Windows
+
 	assembledConsumerGroup sarama.ConsumerGroup
 
 	assembledConsumer wrappedKafka.Consumer
 
-	assembledDecoder kafkaConverter.OrderAssembledDecoder
+	deadLetterSyncProducer sarama.SyncProducer
+	deadLetterProducer     wrappedKafka.Producer
+
+	kafkaClient sarama.Client
+
+	assembledDecoder kafkaConverter.Decoder
+	bindings         *binding.Bindings
+
+	health  *health.Checker
+	metrics *metrics.Metrics
+
+	router   *router.Router
+	renderer *telegramService.Renderer
 
-	telegramClient httpClient.TelegramClient
-	telegramBot    *bot.Bot
+	telegramClient      httpClient.TelegramClient
+	telegramRateLimiter rateLimiter
+	telegramBot         *bot.Bot
 
 	logger *zap.Logger
 	closer *closer.Closer
 }
 
+// rateLimiter — клиент Telegram, лимиты которого можно изменить на лету
+type rateLimiter interface {
+	SetLimits(cfg telegramClient.RateLimitConfig)
+}
+
 func NewDiContainer() *diContainer {
 	return &diContainer{}
 }
@@ -50,9 +87,31 @@ func (d *diContainer) SetCloser(c *closer.Closer) {
 	d.closer = c
 }
 
+func (d *diContainer) Health() *health.Checker {
+	if d.health == nil {
+		d.health = health.NewChecker()
+	}
+
+	return d.health
+}
+
+func (d *diContainer) Metrics() *metrics.Metrics {
+	if d.metrics == nil {
+		d.metrics = metrics.New()
+	}
+
+	return d.metrics
+}
+
 func (d *diContainer) AssembleConsumerService(ctx context.Context) service.ConsumerService {
 	if d.assembleConsumerService == nil {
-		d.assembleConsumerService = assembledConsumer.NewService(d.AssembledConsumer(), d.AssembledDecoder(), d.TelegramService(ctx), d.logger)
+		telegram := d.TelegramService(ctx)
+		if config.AppConfig().Dedup.GetEnabled() {
+			telegram = d.DedupService(telegram)
+		}
+		telegram = maintenanceService.NewService(telegram, d.Maintenance(), d.logger)
+
+		d.assembleConsumerService = assembledConsumer.NewService(d.AssembledConsumer(), d.Bindings(), telegram, d.logger)
 	}
 
 	return d.assembleConsumerService
@@ -60,30 +119,208 @@ func (d *diContainer) AssembleConsumerService(ctx context.Context) service.Consu
 
 func (d *diContainer) TelegramService(ctx context.Context) service.TelegramService {
 	if d.telegramService == nil {
-		d.telegramService = telegramService.NewService(
+		var deliveryJournal telegramService.Journal
+		if config.AppConfig().Journal.GetEnabled() {
+			deliveryJournal = d.Journal()
+		}
+
+		svc := telegramService.NewService(
 			d.TelegramClient(ctx),
 			d.logger,
-			config.AppConfig().TelegramBot.GetTelegramChatID(),
+			d.Router(),
+			d.Renderer(),
+			deliveryJournal,
+			config.AppConfig().TelegramBot.GetMessageConfig(),
 		)
+
+		if cfg := config.AppConfig().Correlation; cfg.GetEnabled() {
+			key, err := eventkey.New(cfg.GetKey())
+			if err != nil {
+				panic(fmt.Sprintf("failed to create correlation key: %s\n", err.Error()))
+			}
+			svc.SetCorrelation(telegramService.Correlation{
+				Key:   key,
+				Mode:  cfg.GetMode(),
+				Store: d.CorrelationStore(),
+			})
+		}
+
+		if config.AppConfig().Routing.GetHoldUsed() {
+			if err := svc.SetHeldStore(d.HeldStore()); err != nil {
+				panic(fmt.Sprintf("failed to restore held notifications: %s\n", err.Error()))
+			}
+		}
+
+		// Отложенные уведомления не отправляются при остановке, а остаются в хранилище.
+		// Хранилище закрывается после завершения начатой отправки
+		d.closer.AddNamed("Held notifications", func(ctx context.Context) error {
+			if err := svc.Close(ctx); err != nil {
+				return err
+			}
+			if d.heldStore != nil {
+				return d.heldStore.Close()
+			}
+			return nil
+		})
+
+		d.telegramService = svc
 	}
 
 	return d.telegramService
 }
 
+// DedupService оборачивает отправку уведомлений дедупликацией событий
+func (d *diContainer) DedupService(next service.TelegramService) service.TelegramService {
+	key, err := eventkey.New(config.AppConfig().Dedup.GetKey())
+	if err != nil {
+		panic(fmt.Sprintf("failed to create dedup key: %s\n", err.Error()))
+	}
+
+	return dedupService.NewService(next, d.DedupStore(), key, d.logger)
+}
+
+func (d *diContainer) DedupStore() dedup.Store {
+	if d.dedupStore == nil {
+		store, err := dedup.New(config.AppConfig().Dedup.Config())
+		if err != nil {
+			panic(fmt.Sprintf("failed to create dedup store: %s\n", err.Error()))
+		}
+		d.closer.AddNamed("Dedup store", func(ctx context.Context) error {
+			return d.dedupStore.Close()
+		})
+
+		d.dedupStore = store
+	}
+
+	return d.dedupStore
+}
+
+func (d *diContainer) Journal() *journal.Journal {
+	if d.journal == nil {
+		j, err := journal.Open(config.AppConfig().Journal.GetPath(), config.AppConfig().Journal.GetRetention())
+		if err != nil {
+			panic(fmt.Sprintf("failed to open delivery journal: %s\n", err.Error()))
+		}
+		d.closer.AddNamed("Delivery journal", func(ctx context.Context) error {
+			return d.journal.Close()
+		})
+
+		d.journal = j
+	}
+
+	return d.journal
+}
+
+func (d *diContainer) CorrelationStore() correlation.Store {
+	if d.correlationStore == nil {
+		cfg := config.AppConfig().Correlation
+		store, err := correlation.New(cfg.GetPath(), cfg.GetTTL())
+		if err != nil {
+			panic(fmt.Sprintf("failed to create correlation store: %s\n", err.Error()))
+		}
+		d.closer.AddNamed("Correlation store", func(ctx context.Context) error {
+			return d.correlationStore.Close()
+		})
+
+		d.correlationStore = store
+	}
+
+	return d.correlationStore
+}
+
+// HeldStore открывает хранилище отложенных уведомлений. Оно закрывается вместе с TelegramService
+func (d *diContainer) HeldStore() *held.Store {
+	if d.heldStore == nil {
+		store, err := held.Open(config.AppConfig().Held.GetPath())
+		if err != nil {
+			panic(fmt.Sprintf("failed to open held notifications store: %s\n", err.Error()))
+		}
+
+		d.heldStore = store
+	}
+
+	return d.heldStore
+}
+
+func (d *diContainer) Maintenance() *maintenance.Windows {
+	if d.maintenance == nil {
+		d.maintenance = maintenance.New(config.AppConfig().Maintenance.GetWindows())
+	}
+
+	return d.maintenance
+}
+
+func (d *diContainer) Renderer() *telegramService.Renderer {
+	if d.renderer == nil {
+		r, err := telegramService.NewRenderer(templateConfig())
+		if err != nil {
+			panic(fmt.Sprintf("failed to load templates: %s\n", err.Error()))
+		}
+
+		d.renderer = r
+	}
+
+	return d.renderer
+}
+
+func (d *diContainer) Router() *router.Router {
+	if d.router == nil {
+		r, err := router.New(routingRules(), config.AppConfig().Routing.GetDefaultRoute())
+		if err != nil {
+			panic(fmt.Sprintf("failed to create router: %s\n", err.Error()))
+		}
+
+		d.router = r
+	}
+
+	return d.router
+}
+
+// templateConfig собирает параметры шаблонов с режимом разметки из конфигурации telegram.
+// Шаблоны привязок topic проверяются раньше правил из секции templates.
+func templateConfig() telegramService.TemplateConfig {
+	cfg := config.AppConfig().Templates.GetTemplates()
+	cfg.ParseMode = config.AppConfig().TelegramBot.GetParseMode()
+	cfg.Rules = append(config.AppConfig().Consumer.GetBindingTemplates(), cfg.Rules...)
+
+	return cfg
+}
+
+// routingRules возвращает правила маршрутизации: сначала маршруты привязок topic,
+// затем правила из секции routing
+func routingRules() []router.Rule {
+	return append(config.AppConfig().Consumer.GetBindingRules(), config.AppConfig().Routing.GetRules()...)
+}
+
 func (d *diContainer) TelegramClient(ctx context.Context) httpClient.TelegramClient {
 	if d.telegramClient == nil {
-		d.telegramClient = telegramClient.NewClient(d.TelegramBot(ctx))
+		c := telegramClient.NewRateLimitedClient(
+			metrics.NewTelegramClient(telegramClient.NewClient(d.TelegramBot(ctx), d.logger), d.Metrics()),
+			config.AppConfig().TelegramBot.GetRateLimit(),
+			d.logger,
+		)
+
+		d.telegramClient = c
+		d.telegramRateLimiter = c
 	}
 
 	return d.telegramClient
 }
 
+func (d *diContainer) TelegramRateLimiter(ctx context.Context) rateLimiter {
+	d.TelegramClient(ctx)
+
+	return d.telegramRateLimiter
+}
+
 func (d *diContainer) TelegramBot(ctx context.Context) *bot.Bot {
 	if d.telegramBot == nil {
+		// bot.New проверяет токен вызовом getMe
 		b, err := bot.New(config.AppConfig().TelegramBot.GetTelegramBotToken())
 		if err != nil {
 			panic(fmt.Sprintf("failed to create telegram bot: %s\n", err.Error()))
 		}
+		d.Health().SetTelegramReady(true)
 
 		d.telegramBot = b
 	}
@@ -93,10 +330,18 @@ func (d *diContainer) TelegramBot(ctx context.Context) *bot.Bot {
 
 func (d *diContainer) AssembledConsumerGroup() sarama.ConsumerGroup {
 	if d.assembledConsumerGroup == nil {
+		consumerConfig, err := config.AppConfig().Consumer.Config()
+		if err != nil {
+			panic(fmt.Sprintf("failed to configure assembled consumer group: %s\n", err.Error()))
+		}
+		if err := config.AppConfig().Kafka.Apply(consumerConfig); err != nil {
+			panic(fmt.Sprintf("failed to configure assembled consumer group: %s\n", err.Error()))
+		}
+
 		consumerGroup, err := sarama.NewConsumerGroup(
 			config.AppConfig().Kafka.GetBrokers(),
 			config.AppConfig().Consumer.GetGroupId(),
-			config.AppConfig().Consumer.Config(),
+			consumerConfig,
 		)
 		if err != nil {
 			panic(fmt.Sprintf("failed to create assembled consumer group: %s\n", err.Error()))
@@ -113,19 +358,159 @@ func (d *diContainer) AssembledConsumerGroup() sarama.ConsumerGroup {
 
 func (d *diContainer) AssembledConsumer() wrappedKafka.Consumer {
 	if d.assembledConsumer == nil {
-		d.assembledConsumer = wrappedKafkaConsumer.NewConsumer(
+		c := wrappedKafkaConsumer.NewConsumer(
 			d.AssembledConsumerGroup(),
-			[]string{
-				config.AppConfig().Consumer.GetTopic(),
-			},
+			d.Bindings().Topics(),
 			d.logger,
+			d.ConsumerMiddlewares()...,
 		)
+		c.SetSessionListener(d.Health().SetConsumerActive)
+
+		// Для привязок по шаблону список topic берётся из метаданных кластера
+		if patterns := d.Bindings().Patterns(); len(patterns) > 0 {
+			c.SetTopicResolver(
+				wrappedKafkaConsumer.NewPatternTopicResolver(d.KafkaClient(), d.Bindings().Topics(), patterns),
+				config.AppConfig().Consumer.GetTopicRefreshInterval(),
+			)
+		}
+
+		d.assembledConsumer = c
 	}
 
 	return d.assembledConsumer
 }
 
-func (d *diContainer) AssembledDecoder() kafkaConverter.OrderAssembledDecoder {
+func (d *diContainer) ConsumerMiddlewares() []wrappedKafkaConsumer.Middleware {
+	middlewares := []wrappedKafkaConsumer.Middleware{d.Metrics().Middleware()}
+
+	if config.AppConfig().DeadLetter.GetEnabled() {
+		middlewares = append(middlewares, wrappedKafkaConsumer.NewDeadLetterMiddleware(d.DeadLetterProducer(), d.logger))
+	}
+
+	middlewares = append(middlewares, wrappedKafkaConsumer.NewRetryMiddleware(config.AppConfig().Consumer.GetRetry(), d.logger))
+
+	return middlewares
+}
+
+func (d *diContainer) DeadLetterSyncProducer() sarama.SyncProducer {
+	if d.deadLetterSyncProducer == nil {
+		producerConfig := config.AppConfig().DeadLetter.Config()
+		if err := config.AppConfig().Kafka.Apply(producerConfig); err != nil {
+			panic(fmt.Sprintf("failed to configure dead-letter sync producer: %s\n", err.Error()))
+		}
+
+		syncProducer, err := sarama.NewSyncProducer(
+			config.AppConfig().Kafka.GetBrokers(),
+			producerConfig,
+		)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create dead-letter sync producer: %s\n", err.Error()))
+		}
+		d.closer.AddNamed("Kafka dead-letter producer", func(ctx context.Context) error {
+			return d.deadLetterSyncProducer.Close()
+		})
+
+		d.deadLetterSyncProducer = syncProducer
+	}
+
+	return d.deadLetterSyncProducer
+}
+
+func (d *diContainer) DeadLetterProducer() wrappedKafka.Producer {
+	if d.deadLetterProducer == nil {
+		d.deadLetterProducer = wrappedKafkaProducer.NewProducer(
+			d.DeadLetterSyncProducer(),
+			config.AppConfig().DeadLetter.GetTopic(),
+			d.logger,
+		)
+	}
+
+	return d.deadLetterProducer
+}
+
+func (d *diContainer) KafkaClient() sarama.Client {
+	if d.kafkaClient == nil {
+		clientConfig, err := config.AppConfig().Consumer.Config()
+		if err != nil {
+			panic(fmt.Sprintf("failed to configure kafka client: %s\n", err.Error()))
+		}
+		if err := config.AppConfig().Kafka.Apply(clientConfig); err != nil {
+			panic(fmt.Sprintf("failed to configure kafka client: %s\n", err.Error()))
+		}
+
+		client, err := sarama.NewClient(config.AppConfig().Kafka.GetBrokers(), clientConfig)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create kafka client: %s\n", err.Error()))
+		}
+		d.closer.AddNamed("Kafka client", func(ctx context.Context) error {
+			return d.kafkaClient.Close()
+		})
+
+		d.kafkaClient = client
+	}
+
+	return d.kafkaClient
+}
+
+func (d *diContainer) Bindings() *binding.Bindings {
+	if d.bindings == nil {
+		var bindings []binding.Binding
+		for _, cfg := range config.AppConfig().Consumer.GetBindings() {
+			bindings = append(bindings, d.binding(cfg))
+		}
+
+		d.bindings = binding.New(bindings, d.binding(config.AppConfig().Consumer.GetDefaultBinding()))
+	}
+
+	return d.bindings
+}
+
+func (d *diContainer) binding(cfg binding.Config) binding.Binding {
+	b := binding.Binding{
+		Name:           cfg.Name,
+		Topic:          cfg.Topic,
+		Decoder:        d.Decoder(cfg),
+		SeverityHeader: cfg.SeverityHeader,
+	}
+
+	if cfg.Pattern != "" {
+		pattern, err := binding.Compile(cfg.Pattern)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create binding %s: %s\n", cfg.Name, err.Error()))
+		}
+		b.Pattern = pattern
+	}
+
+	return b
+}
+
+// Decoder возвращает декодер сообщений, указанный в привязке topic
+func (d *diContainer) Decoder(cfg binding.Config) kafkaConverter.Decoder {
+	switch cfg.Decoder {
+	case "", decoder.NameAssembled:
+		return d.AssembledDecoder()
+	case decoder.NameProtobuf:
+		dec, err := decoder.NewProtobufDecoder(cfg.Protobuf)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create protobuf decoder for binding %s: %s\n", cfg.Name, err.Error()))
+		}
+		return dec
+	case decoder.NameAvro:
+		dec, err := decoder.NewAvroDecoder(cfg.Avro)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create avro decoder for binding %s: %s\n", cfg.Name, err.Error()))
+		}
+		return dec
+	case decoder.NameCloudEvents:
+		return decoder.NewCloudEventsDecoder(cfg.CloudEvents)
+	case decoder.NameJSON:
+		return decoder.NewJSONDecoder(cfg.JSON)
+	default:
+		panic(fmt.Sprintf("unknown decoder %q\n", cfg.Decoder))
+	}
+}
+
+func (d *diContainer) AssembledDecoder() kafkaConverter.Decoder {
 	if d.assembledDecoder == nil {
 		d.assembledDecoder = decoder.NewOrderDecoderAssembled()
 	}
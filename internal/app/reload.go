@@ -0,0 +1,195 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/major1ink/simple-notification-telegram/internal/config"
+	"github.com/major1ink/simple-notification-telegram/internal/logger"
+)
+
+// reloadDebounce — пауза после изменения файла, чтобы дождаться окончания записи
+const reloadDebounce = 500 * time.Millisecond
+
+// runConfigReloader перечитывает конфигурацию по SIGHUP и при изменении файла конфигурации,
+// а шаблоны — при изменении файлов в templates.dir
+func (a *App) runConfigReloader(ctx context.Context) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	path := configPath()
+	content, _ := os.ReadFile(path)
+
+	// Следим за директорией, а не за файлом: редакторы и Kubernetes заменяют файл
+	// переименованием, и наблюдение за самим файлом теряется
+	var (
+		watcher    *fsnotify.Watcher
+		fileEvents <-chan fsnotify.Event
+	)
+	if w, err := fsnotify.NewWatcher(); err != nil {
+		a.logger.Error("Failed to watch config files, reload only by SIGHUP", zap.Error(err))
+	} else {
+		watcher = w
+		defer watcher.Close()
+		fileEvents = watcher.Events
+
+		if path != "" {
+			if err := watcher.Add(filepath.Dir(path)); err != nil {
+				a.logger.Error("Failed to watch config file, reload only by SIGHUP", zap.Error(err))
+			}
+		}
+	}
+
+	templates := &templateWatch{watcher: watcher, logger: a.logger}
+	if path != "" {
+		templates.configDir = filepath.Dir(path)
+	}
+	defer templates.stop()
+	templates.watch(templateConfig().Dir)
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	templatesDebounce := time.NewTimer(reloadDebounce)
+	templatesDebounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.closer.Done():
+			return
+		case <-sighup:
+			a.logger.Info("SIGHUP received, reloading configuration")
+			content, _ = os.ReadFile(path)
+			a.reloadConfig(ctx)
+			templates.watch(templateConfig().Dir)
+		case event, ok := <-fileEvents:
+			if !ok {
+				fileEvents = nil
+				continue
+			}
+			if path != "" && filepath.Dir(event.Name) == filepath.Dir(path) {
+				debounce.Reset(reloadDebounce)
+			}
+			if templates.contains(event.Name) {
+				templatesDebounce.Reset(reloadDebounce)
+			}
+		case <-debounce.C:
+			updated, err := os.ReadFile(path)
+			if err != nil || bytes.Equal(updated, content) {
+				continue
+			}
+			content = updated
+
+			a.logger.Info("Config file changed, reloading configuration", zap.String("path", path))
+			a.reloadConfig(ctx)
+			templates.watch(templateConfig().Dir)
+		case <-templatesDebounce.C:
+			a.logger.Info("Templates changed, reloading templates", zap.String("dir", templates.dir))
+			// Новые поддиректории тоже нужно отслеживать
+			templates.watch(templates.dir)
+			if err := a.diContainer.Renderer().Update(templateConfig()); err != nil {
+				a.logger.Error("Failed to reload templates, keeping current ones", zap.Error(err))
+			}
+		}
+	}
+}
+
+// templateWatch отслеживает директорию шаблонов вместе с поддиректориями
+type templateWatch struct {
+	watcher *fsnotify.Watcher
+	logger  *zap.Logger
+	// configDir — директория файла конфигурации, наблюдение за ней не снимается
+	configDir string
+	dir       string
+	watched   []string
+}
+
+// watch переключает наблюдение на директорию dir
+func (t *templateWatch) watch(dir string) {
+	if t.watcher == nil {
+		return
+	}
+	t.stop()
+
+	t.dir = dir
+	if dir == "" {
+		return
+	}
+	t.dir = filepath.Clean(dir)
+
+	err := filepath.WalkDir(t.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		if err := t.watcher.Add(path); err != nil {
+			return err
+		}
+		t.watched = append(t.watched, path)
+		return nil
+	})
+	if err != nil {
+		t.logger.Error("Failed to watch templates, reload them by SIGHUP", zap.String("dir", dir), zap.Error(err))
+	}
+}
+
+func (t *templateWatch) stop() {
+	for _, path := range t.watched {
+		if path != t.configDir {
+			_ = t.watcher.Remove(path)
+		}
+	}
+	t.watched = nil
+}
+
+// contains сообщает, что файл находится в директории шаблонов
+func (t *templateWatch) contains(name string) bool {
+	if t.dir == "" {
+		return false
+	}
+
+	rel, err := filepath.Rel(t.dir, name)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// reloadConfig применяет новую конфигурацию: уровень логирования, шаблоны,
+// маршрутизацию, лимиты Telegram и окна обслуживания. При ошибке продолжает работать текущая конфигурация.
+func (a *App) reloadConfig(ctx context.Context) {
+	restart, err := config.Reload(configPath())
+	if err != nil {
+		a.logger.Error("Failed to reload configuration, keeping current one", zap.Error(err))
+		return
+	}
+
+	logger.SetLevel(config.AppConfig().Logger.GetLevel())
+	a.logConfigWarnings()
+
+	if err := a.diContainer.Renderer().Update(templateConfig()); err != nil {
+		a.logger.Error("Failed to reload templates", zap.Error(err))
+	}
+
+	if err := a.diContainer.Router().Update(routingRules(), config.AppConfig().Routing.GetDefaultRoute()); err != nil {
+		a.logger.Error("Failed to reload routing", zap.Error(err))
+	}
+
+	a.diContainer.TelegramRateLimiter(ctx).SetLimits(config.AppConfig().TelegramBot.GetRateLimit())
+
+	a.diContainer.Maintenance().Update(config.AppConfig().Maintenance.GetWindows())
+
+	for _, key := range restart {
+		a.logger.Warn("Configuration change requires restart and was not applied", zap.String("key", key))
+	}
+
+	a.logger.Info("Configuration reloaded")
+}
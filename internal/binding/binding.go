@@ -0,0 +1,107 @@
+package binding
+
+import (
+	"fmt"
+	"regexp"
+
+	kafkaConverter "github.com/major1ink/simple-notification-telegram/internal/converter/kafka"
+	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+)
+
+// DefaultName — имя привязки по умолчанию для topic без собственной привязки
+const DefaultName = "default"
+
+// Config — описание привязки topic из конфигурации
+type Config struct {
+	Name    string
+	Topic   string // Точное имя topic
+	Pattern string // Регулярное выражение для имён topic
+	Decoder string // Имя декодера сообщений
+	// SeverityHeader — заголовок kafka с уровнем важности (приоритетнее поля сообщения)
+	SeverityHeader string
+
+	Protobuf    decoder.ProtobufConfig
+	Avro        decoder.AvroConfig
+	CloudEvents decoder.CloudEventsConfig
+	JSON        decoder.JSONConfig
+}
+
+// Binding — привязка topic к декодеру
+type Binding struct {
+	Name    string
+	Topic   string
+	Pattern *regexp.Regexp
+	Decoder kafkaConverter.Decoder
+
+	SeverityHeader string
+}
+
+// Bindings выбирает привязку для topic. Привязки проверяются по порядку,
+// для topic без совпадений используется привязка по умолчанию.
+type Bindings struct {
+	bindings []Binding
+	def      Binding
+}
+
+func New(bindings []Binding, def Binding) *Bindings {
+	if def.Name == "" {
+		def.Name = DefaultName
+	}
+
+	return &Bindings{
+		bindings: bindings,
+		def:      def,
+	}
+}
+
+// Compile компилирует шаблон имени topic
+func Compile(pattern string) (*regexp.Regexp, error) {
+	// Шаблон должен совпадать с именем topic целиком
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic pattern %q: %w", pattern, err)
+	}
+
+	return re, nil
+}
+
+// Match возвращает привязку для topic
+func (b *Bindings) Match(topic string) Binding {
+	for _, binding := range b.bindings {
+		if binding.Topic != "" && binding.Topic == topic {
+			return binding
+		}
+		if binding.Pattern != nil && binding.Pattern.MatchString(topic) {
+			return binding
+		}
+	}
+
+	return b.def
+}
+
+// Topics возвращает имена topic, заданные явно, включая topic привязки по умолчанию
+func (b *Bindings) Topics() []string {
+	var topics []string
+	if b.def.Topic != "" {
+		topics = append(topics, b.def.Topic)
+	}
+	for _, binding := range b.bindings {
+		if binding.Topic != "" {
+			topics = append(topics, binding.Topic)
+		}
+	}
+
+	return topics
+}
+
+// Patterns возвращает шаблоны имён topic
+func (b *Bindings) Patterns() []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, binding := range b.bindings {
+		if binding.Pattern != nil {
+			patterns = append(patterns, binding.Pattern)
+		}
+	}
+
+	return patterns
+}
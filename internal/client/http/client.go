@@ -1,7 +1,21 @@
 package http
 
-import "context"
+import (
+	"context"
+	"errors"
 
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// ErrRejected — Telegram окончательно отклонил сообщение, повторная отправка не поможет.
+var ErrRejected = errors.New("telegram rejected message")
+
+// TelegramClient отправляет сообщения в Telegram и возвращает message_id отправленного сообщения
 type TelegramClient interface {
-	SendMessage(ctx context.Context, chatID int64, text string) error
+	SendMessage(ctx context.Context, msg model.TelegramMessage) (int, error)
+	SendDocument(ctx context.Context, doc model.TelegramDocument) (int, error)
+	// SendReply отправляет сообщение ответом на сообщение replyTo
+	SendReply(ctx context.Context, msg model.TelegramMessage, replyTo int) (int, error)
+	// EditMessage заменяет текст ранее отправленного сообщения
+	EditMessage(ctx context.Context, edit model.TelegramEdit) error
 }
@@ -1,32 +1,168 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+
+	def "github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
 )
 
 type client struct {
-	bot *bot.Bot
+	bot    *bot.Bot
+	logger *zap.Logger
 }
 
 // NewClient создает новый клиент для Telegram Bot API
-func NewClient(bot *bot.Bot) *client {
+func NewClient(bot *bot.Bot, logger *zap.Logger) *client {
 	return &client{
-		bot: bot,
+		bot:    bot,
+		logger: logger,
 	}
 }
 
-// SendMessage отправляет сообщение в указанный чат
-func (c *client) SendMessage(ctx context.Context, chatID int64, text string) error {
-	_, err := c.bot.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    chatID,
-		Text:      text,
-		ParseMode: "Markdown",
+// SendMessage отправляет сообщение в указанный чат.
+// Если Telegram не смог разобрать разметку, сообщение однократно отправляется простым текстом.
+func (c *client) SendMessage(ctx context.Context, msg model.TelegramMessage) (int, error) {
+	return c.sendMessage(ctx, msg, nil)
+}
+
+// SendReply отправляет сообщение ответом на replyTo. Если исходное сообщение
+// удалено, сообщение отправляется без ответа.
+func (c *client) SendReply(ctx context.Context, msg model.TelegramMessage, replyTo int) (int, error) {
+	return c.sendMessage(ctx, msg, &models.ReplyParameters{
+		MessageID:                replyTo,
+		AllowSendingWithoutReply: true,
 	})
+}
+
+func (c *client) sendMessage(ctx context.Context, msg model.TelegramMessage, reply *models.ReplyParameters) (int, error) {
+	send := func(parseMode model.ParseMode) (*models.Message, error) {
+		return c.bot.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:              msg.ChatID,
+			MessageThreadID:     msg.ThreadID,
+			Text:                msg.Text,
+			ParseMode:           models.ParseMode(parseMode),
+			DisableNotification: msg.DisableNotification,
+			ReplyParameters:     reply,
+		})
+	}
+
+	sent, err := send(msg.ParseMode)
+	if isParseError(err) && msg.ParseMode != model.ParseModePlain {
+		c.logger.Warn("Telegram failed to parse message entities, resending as plain text",
+			zap.Int64("chat_id", msg.ChatID),
+			zap.String("parse_mode", string(msg.ParseMode)),
+			zap.Error(err),
+		)
+		sent, err = send(model.ParseModePlain)
+	}
 	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return messageID(sent), nil
+}
+
+// SendDocument отправляет документ в указанный чат.
+// Если Telegram не смог разобрать разметку подписи, она однократно отправляется простым текстом.
+func (c *client) SendDocument(ctx context.Context, doc model.TelegramDocument) (int, error) {
+	send := func(parseMode model.ParseMode) (*models.Message, error) {
+		return c.bot.SendDocument(ctx, &bot.SendDocumentParams{
+			ChatID:          doc.ChatID,
+			MessageThreadID: doc.ThreadID,
+			Document: &models.InputFileUpload{
+				Filename: doc.Filename,
+				Data:     bytes.NewReader(doc.Data),
+			},
+			Caption:             doc.Caption,
+			ParseMode:           models.ParseMode(parseMode),
+			DisableNotification: doc.DisableNotification,
+		})
+	}
+
+	sent, err := send(doc.ParseMode)
+	if isParseError(err) && doc.ParseMode != model.ParseModePlain {
+		c.logger.Warn("Telegram failed to parse caption entities, resending as plain text",
+			zap.Int64("chat_id", doc.ChatID),
+			zap.String("parse_mode", string(doc.ParseMode)),
+			zap.Error(err),
+		)
+		sent, err = send(model.ParseModePlain)
+	}
+	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return messageID(sent), nil
+}
+
+// EditMessage заменяет текст сообщения. Повтор того же текста не считается ошибкой.
+// Если Telegram не смог разобрать разметку, текст однократно отправляется простым текстом.
+func (c *client) EditMessage(ctx context.Context, edit model.TelegramEdit) error {
+	send := func(parseMode model.ParseMode) error {
+		_, err := c.bot.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    edit.ChatID,
+			MessageID: edit.MessageID,
+			Text:      edit.Text,
+			ParseMode: models.ParseMode(parseMode),
+		})
 		return err
 	}
 
+	err := send(edit.ParseMode)
+	if isParseError(err) && edit.ParseMode != model.ParseModePlain {
+		c.logger.Warn("Telegram failed to parse message entities, resending edit as plain text",
+			zap.Int64("chat_id", edit.ChatID),
+			zap.Int("message_id", edit.MessageID),
+			zap.String("parse_mode", string(edit.ParseMode)),
+			zap.Error(err),
+		)
+		err = send(model.ParseModePlain)
+	}
+	if isNotModifiedError(err) {
+		return nil
+	}
+	if err != nil {
+		return classifyError(err)
+	}
+
 	return nil
 }
+
+func messageID(msg *models.Message) int {
+	if msg == nil {
+		return 0
+	}
+
+	return msg.ID
+}
+
+// isParseError сообщает, что Telegram отклонил сообщение из-за ошибки разметки
+func isParseError(err error) bool {
+	return errors.Is(err, bot.ErrorBadRequest) && strings.Contains(err.Error(), "can't parse entities")
+}
+
+// isNotModifiedError сообщает, что новый текст совпадает с текущим
+func isNotModifiedError(err error) bool {
+	return errors.Is(err, bot.ErrorBadRequest) && strings.Contains(err.Error(), "message is not modified")
+}
+
+// classifyError помечает ошибки, после которых повторять отправку бессмысленно
+func classifyError(err error) error {
+	if errors.Is(err, bot.ErrorBadRequest) ||
+		errors.Is(err, bot.ErrorForbidden) ||
+		errors.Is(err, bot.ErrorNotFound) ||
+		bot.IsMigrateError(err) {
+		return fmt.Errorf("%w: %w", def.ErrRejected, err)
+	}
+
+	return err
+}
@@ -0,0 +1,246 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	def "github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// chatIdleTTL — через сколько неиспользуемый лимитер чата удаляется
+const chatIdleTTL = 10 * time.Minute
+
+// RateLimitConfig — ограничения частоты отправки сообщений в Telegram Bot API
+type RateLimitConfig struct {
+	GlobalPerSecond       float64 // Общее количество сообщений в секунду
+	GlobalBurst           int     // Допустимый всплеск для общего лимита
+	PerChatPerMinute      float64 // Количество сообщений в минуту в одну группу или канал
+	PrivatePerSecond      float64 // Количество сообщений в секунду в один личный чат
+	PerChatBurst          int     // Допустимый всплеск для лимита чата
+	MaxRetryAfterAttempts int     // Сколько раз повторять отправку после ответа 429
+}
+
+// DefaultRateLimitConfig возвращает ограничения, соответствующие лимитам Telegram
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		GlobalPerSecond:       30,
+		GlobalBurst:           30,
+		PerChatPerMinute:      20,
+		PrivatePerSecond:      1,
+		PerChatBurst:          3,
+		MaxRetryAfterAttempts: 5,
+	}
+}
+
+type chatLimiter struct {
+	limiter     *rate.Limiter
+	pausedUntil time.Time
+	usedAt      time.Time
+}
+
+type rateLimitedClient struct {
+	next   def.TelegramClient
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	cfg    RateLimitConfig
+	global *rate.Limiter
+	// globalPausedUntil — до какого момента все отправки ждут после ответа 429
+	globalPausedUntil time.Time
+	chats             map[int64]*chatLimiter
+	prunedAt          time.Time
+}
+
+// NewRateLimitedClient оборачивает клиент ограничением частоты отправки.
+// При превышении лимитов сообщения ожидают своей очереди, а ответы 429
+// обрабатываются ожиданием retry_after и повторной отправкой.
+func NewRateLimitedClient(next def.TelegramClient, cfg RateLimitConfig, logger *zap.Logger) *rateLimitedClient {
+	c := &rateLimitedClient{
+		next:   next,
+		logger: logger,
+		chats:  make(map[int64]*chatLimiter),
+	}
+	c.SetLimits(cfg)
+
+	return c
+}
+
+// SetLimits применяет новые ограничения частоты отправки
+func (c *rateLimitedClient) SetLimits(cfg RateLimitConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg = cfg
+	if c.global == nil {
+		c.global = rate.NewLimiter(rate.Limit(cfg.GlobalPerSecond), cfg.GlobalBurst)
+	} else {
+		c.global.SetLimit(rate.Limit(cfg.GlobalPerSecond))
+		c.global.SetBurst(cfg.GlobalBurst)
+	}
+
+	for chatID, chat := range c.chats {
+		chat.limiter.SetLimit(c.chatLimit(chatID))
+		chat.limiter.SetBurst(cfg.PerChatBurst)
+	}
+}
+
+// SendMessage отправляет сообщение с учётом ограничений частоты
+func (c *rateLimitedClient) SendMessage(ctx context.Context, msg model.TelegramMessage) (int, error) {
+	return c.do(ctx, msg.ChatID, func() (int, error) {
+		return c.next.SendMessage(ctx, msg)
+	})
+}
+
+// SendDocument отправляет документ с учётом ограничений частоты
+func (c *rateLimitedClient) SendDocument(ctx context.Context, doc model.TelegramDocument) (int, error) {
+	return c.do(ctx, doc.ChatID, func() (int, error) {
+		return c.next.SendDocument(ctx, doc)
+	})
+}
+
+// SendReply отправляет ответ на сообщение с учётом ограничений частоты
+func (c *rateLimitedClient) SendReply(ctx context.Context, msg model.TelegramMessage, replyTo int) (int, error) {
+	return c.do(ctx, msg.ChatID, func() (int, error) {
+		return c.next.SendReply(ctx, msg, replyTo)
+	})
+}
+
+// EditMessage изменяет сообщение с учётом ограничений частоты
+func (c *rateLimitedClient) EditMessage(ctx context.Context, edit model.TelegramEdit) error {
+	_, err := c.do(ctx, edit.ChatID, func() (int, error) {
+		return edit.MessageID, c.next.EditMessage(ctx, edit)
+	})
+
+	return err
+}
+
+func (c *rateLimitedClient) do(ctx context.Context, chatID int64, send func() (int, error)) (int, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx, chatID); err != nil {
+			return 0, err
+		}
+
+		messageID, err := send()
+
+		var tooMany *bot.TooManyRequestsError
+		if !errors.As(err, &tooMany) || attempt >= c.maxRetryAfterAttempts() {
+			return messageID, err
+		}
+
+		retryAfter := time.Duration(tooMany.RetryAfter) * time.Second
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+
+		c.logger.Warn("Telegram rate limit exceeded, waiting before retry",
+			zap.Int64("chat_id", chatID),
+			zap.Duration("retry_after", retryAfter),
+			zap.Int("attempt", attempt+1),
+		)
+		c.pause(chatID, retryAfter)
+	}
+}
+
+func (c *rateLimitedClient) wait(ctx context.Context, chatID int64) error {
+	chat := c.chat(chatID)
+
+	c.mu.Lock()
+	pause := time.Until(chat.pausedUntil)
+	if global := time.Until(c.globalPausedUntil); global > pause {
+		pause = global
+	}
+	c.mu.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err := chat.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	return c.global.Wait(ctx)
+}
+
+// pause приостанавливает отправку в чат и, так как flood control Telegram учитывает
+// все сообщения бота, остальные отправки тоже
+func (c *rateLimitedClient) pause(chatID int64, d time.Duration) {
+	chat := c.chat(chatID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(chat.pausedUntil) {
+		chat.pausedUntil = until
+	}
+	if until.After(c.globalPausedUntil) {
+		c.globalPausedUntil = until
+	}
+}
+
+func (c *rateLimitedClient) chat(chatID int64) *chatLimiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.prunedAt) > chatIdleTTL {
+		c.prune(now)
+	}
+
+	chat, ok := c.chats[chatID]
+	if !ok {
+		chat = &chatLimiter{
+			limiter: rate.NewLimiter(c.chatLimit(chatID), c.cfg.PerChatBurst),
+		}
+		c.chats[chatID] = chat
+	}
+	chat.usedAt = now
+
+	return chat
+}
+
+// prune удаляет лимитеры чатов, в которые давно ничего не отправлялось:
+// за это время их лимит полностью восстановился
+func (c *rateLimitedClient) prune(now time.Time) {
+	for chatID, chat := range c.chats {
+		if now.Sub(chat.usedAt) > chatIdleTTL && now.After(chat.pausedUntil) {
+			delete(c.chats, chatID)
+		}
+	}
+	c.prunedAt = now
+}
+
+// chatLimit возвращает лимит чата: у групп и каналов (отрицательный chat_id) он
+// в минуту, у личных чатов — в секунду
+func (c *rateLimitedClient) chatLimit(chatID int64) rate.Limit {
+	if chatID < 0 {
+		return perMinute(c.cfg.PerChatPerMinute)
+	}
+
+	return rate.Limit(c.cfg.PrivatePerSecond)
+}
+
+func (c *rateLimitedClient) maxRetryAfterAttempts() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cfg.MaxRetryAfterAttempts
+}
+
+func perMinute(n float64) rate.Limit {
+	return rate.Limit(n / 60)
+}
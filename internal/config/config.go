@@ -1,56 +1,206 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync/atomic"
 
 	structYaml "github.com/major1ink/simple-notification-telegram/internal/config/yaml"
 	"gopkg.in/yaml.v3"
 )
 
-var appConfig *config
+var (
+	appConfig atomic.Pointer[config]
+	// loaded хранит исходную конфигурацию и номера строк ключей для валидации
+	loaded atomic.Pointer[loadedConfig]
+)
+
+type loadedConfig struct {
+	raw   yamlConfig
+	lines lineIndex
+	// defaultRouteFromChatID — маршрут по умолчанию построен из telegram_chat_id
+	defaultRouteFromChatID bool
+	// interpolated — пути обычных строковых полей со значениями из ${...}, скрываемые при выводе
+	interpolated map[string]bool
+	// warnings — подозрительные, но допустимые значения, найденные при проверке
+	warnings []Problem
+}
 
 type config struct {
 	Logger      LoggerConfig
 	Kafka       KafkaConfig
 	Consumer    ConsumerConfig
 	TelegramBot TelegramConfig
+	DeadLetter  DeadLetterConfig
+	Dedup       DedupConfig
+	Journal     JournalConfig
+	Correlation CorrelationConfig
+	Held        HeldConfig
+	Maintenance MaintenanceConfig
+	Routing     RoutingConfig
+	Templates   TemplatesConfig
+	HTTPServer  HTTPServerConfig
+}
+
+type yamlConfig struct {
+	Logger      *structYaml.LoggerConfig      `yaml:"logger"`
+	Kafka       *structYaml.KafkaConfig       `yaml:"kafkaConfig"`
+	Consumer    *structYaml.ConsumerConfig    `yaml:"consumerConfig"`
+	Telegram    *structYaml.TelegramConfig    `yaml:"telegramConfig"`
+	DeadLetter  *structYaml.DeadLetterConfig  `yaml:"deadLetterConfig"`
+	Dedup       *structYaml.DedupConfig       `yaml:"dedupConfig"`
+	Journal     *structYaml.JournalConfig     `yaml:"journalConfig"`
+	Correlation *structYaml.CorrelationConfig `yaml:"correlationConfig"`
+	Held        *structYaml.HeldConfig        `yaml:"heldConfig"`
+	Maintenance *structYaml.MaintenanceConfig `yaml:"maintenance"`
+	Routing     *structYaml.RoutingConfig     `yaml:"routing"`
+	Templates   *structYaml.TemplatesConfig   `yaml:"templates"`
+	HTTPServer  *structYaml.HTTPServerConfig  `yaml:"httpServer"`
 }
 
+// Load читает конфигурацию из YAML файла и переменных окружения SNT_*.
+// Переменные окружения имеют приоритет над файлом. Если путь пустой,
+// конфигурация собирается только из переменных окружения.
+// Подстановки ${file:...} и ${env:...} разрешаются после переопределений.
 func Load(path ...string) error {
 	var configPath string
 	if len(path) > 0 && path[0] != "" {
 		configPath = path[0]
 	}
 
-	file, err := os.Open(configPath)
+	l, err := load(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to open config file: %w", err)
+		return err
 	}
-	defer file.Close()
+	store(l)
 
-	var yamlConfig struct {
-		Logger   *structYaml.LoggerConfig   `yaml:"logger"`
-		Kafka    *structYaml.KafkaConfig    `yaml:"kafkaConfig"`
-		Consumer *structYaml.ConsumerConfig `yaml:"consumerConfig"`
-		Telegram *structYaml.TelegramConfig `yaml:"telegramConfig"`
+	return nil
+}
+
+// Reload перечитывает и проверяет конфигурацию, затем атомарно заменяет текущую.
+// При ошибке текущая конфигурация не меняется. Возвращает ключи, изменение которых
+// требует перезапуска: для них сохраняются действующие значения.
+func Reload(path string) ([]string, error) {
+	l, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(l); err != nil {
+		return nil, err
 	}
 
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&yamlConfig); err != nil {
-		return fmt.Errorf("failed to decode config file: %w", err)
+	var restart []string
+	if prev := loaded.Load(); prev != nil {
+		restart = keepRestartSettings(&prev.raw, &l.raw)
 	}
+	store(l)
+
+	return restart, nil
+}
+
+func load(configPath string) (*loadedConfig, error) {
+	var (
+		cfg   yamlConfig
+		lines = lineIndex{}
+	)
 
-	appConfig = &config{
-		Logger:      yamlConfig.Logger,
-		Kafka:       yamlConfig.Kafka,
-		Consumer:    yamlConfig.Consumer,
-		TelegramBot: yamlConfig.Telegram,
+	if configPath != "" {
+		file, err := os.Open(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open config file: %w", err)
+		}
+		defer file.Close()
+
+		var root yaml.Node
+		decoder := yaml.NewDecoder(file)
+		if err := decoder.Decode(&root); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode config file: %w", err)
+		}
+		if root.Kind != 0 {
+			if err := root.Decode(&cfg); err != nil {
+				return nil, fmt.Errorf("failed to decode config file: %w", err)
+			}
+			lines.index(&root, "")
+		}
 	}
 
-	return nil
+	if err := applyEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+	interpolated, err := resolveSecrets(&cfg, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Logger == nil {
+		cfg.Logger = &structYaml.LoggerConfig{LogLevel: "info", LogMode: "stdout"}
+	}
+	if cfg.DeadLetter == nil {
+		cfg.DeadLetter = &structYaml.DeadLetterConfig{}
+	}
+	if cfg.Dedup == nil {
+		cfg.Dedup = &structYaml.DedupConfig{}
+	}
+	if cfg.Journal == nil {
+		cfg.Journal = &structYaml.JournalConfig{}
+	}
+	if cfg.Correlation == nil {
+		cfg.Correlation = &structYaml.CorrelationConfig{}
+	}
+	if cfg.Held == nil {
+		cfg.Held = &structYaml.HeldConfig{}
+	}
+	if cfg.Maintenance == nil {
+		cfg.Maintenance = &structYaml.MaintenanceConfig{}
+	}
+
+	if cfg.HTTPServer == nil {
+		cfg.HTTPServer = &structYaml.HTTPServerConfig{}
+	}
+	if cfg.Templates == nil {
+		cfg.Templates = &structYaml.TemplatesConfig{}
+	}
+	if cfg.Routing == nil {
+		cfg.Routing = &structYaml.RoutingConfig{}
+	}
+	// Без явного маршрута по умолчанию события уходят в telegram_chat_id
+	defaultRouteFromChatID := false
+	if cfg.Routing.Default == nil && cfg.Telegram != nil {
+		cfg.Routing.Default = &structYaml.RouteConfig{
+			ChatIDs: []int64{cfg.Telegram.TelegramChatID},
+		}
+		defaultRouteFromChatID = true
+	}
+
+	return &loadedConfig{
+		raw:                    cfg,
+		lines:                  lines,
+		defaultRouteFromChatID: defaultRouteFromChatID,
+		interpolated:           interpolated,
+	}, nil
+}
+
+func store(l *loadedConfig) {
+	loaded.Store(l)
+	appConfig.Store(&config{
+		Logger:      l.raw.Logger,
+		Kafka:       l.raw.Kafka,
+		Consumer:    l.raw.Consumer,
+		TelegramBot: l.raw.Telegram,
+		DeadLetter:  l.raw.DeadLetter,
+		Dedup:       l.raw.Dedup,
+		Journal:     l.raw.Journal,
+		Correlation: l.raw.Correlation,
+		Held:        l.raw.Held,
+		Maintenance: l.raw.Maintenance,
+		Routing:     l.raw.Routing,
+		Templates:   l.raw.Templates,
+		HTTPServer:  l.raw.HTTPServer,
+	})
 }
 
 func AppConfig() *config {
-	return appConfig
+	return appConfig.Load()
 }
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix — префикс переменных окружения, переопределяющих конфигурацию
+const envPrefix = "SNT"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv переопределяет значения конфигурации переменными окружения.
+// Имя переменной строится из пути к ключу: SNT_<СЕКЦИЯ>_<КЛЮЧ>, например
+// telegramConfig.telegram_bot_token -> SNT_TELEGRAM_BOT_TOKEN,
+// consumerConfig.retry.max_attempts -> SNT_CONSUMER_RETRY_MAX_ATTEMPTS.
+// Списки скаляров задаются через запятую, списки объектов — в формате YAML/JSON.
+func applyEnv(cfg any) error {
+	_, err := applyEnvStruct(reflect.ValueOf(cfg).Elem(), envPrefix, "", true)
+	return err
+}
+
+// envKey возвращает сегмент имени переменной для ключа YAML.
+// Для секций отбрасывается суффикс Config, а у вложенных ключей — повтор имени
+// родителя: telegramConfig.telegram_bot_token -> TELEGRAM, BOT_TOKEN.
+func envKey(parent, tag string, section bool) string {
+	if section {
+		return envSegment(strings.TrimSuffix(tag, "Config"))
+	}
+
+	key := envSegment(tag)
+	if parent != "" && strings.HasPrefix(key, parent+"_") {
+		key = strings.TrimPrefix(key, parent+"_")
+	}
+
+	return key
+}
+
+// envSegment переводит ключ YAML (camelCase или snake_case) в UPPER_SNAKE_CASE
+func envSegment(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		if r == '-' {
+			r = '_'
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// applyEnvStruct заполняет поля структуры и сообщает, было ли что-либо изменено
+func applyEnvStruct(v reflect.Value, prefix, parent string, section bool) (bool, error) {
+	changed := false
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		// Встроенные структуры (yaml:",inline") разворачиваются на том же уровне
+		if tag == "" && field.Anonymous {
+			ok, err := applyEnvStruct(v.Field(i), prefix, parent, false)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || ok
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+
+		key := envKey(parent, tag, section)
+		ok, err := applyEnvValue(v.Field(i), prefix+"_"+key, key)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || ok
+	}
+
+	return changed, nil
+}
+
+func applyEnvValue(v reflect.Value, name, key string) (bool, error) {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			elem.Elem().Set(v.Elem())
+		}
+
+		ok, err := applyEnvValue(elem.Elem(), name, key)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		v.Set(elem)
+		return true, nil
+	}
+
+	if v.Kind() == reflect.Struct {
+		return applyEnvStruct(v, name, key, false)
+	}
+
+	raw, ok := os.LookupEnv(name)
+	if !ok {
+		return false, nil
+	}
+
+	if err := setValue(v, raw); err != nil {
+		return false, fmt.Errorf("invalid value of %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if isScalar(v.Type().Elem()) {
+			return setScalarSlice(v, raw)
+		}
+		return yaml.Unmarshal([]byte(raw), v.Addr().Interface())
+	default:
+		return yaml.Unmarshal([]byte(raw), v.Addr().Interface())
+	}
+
+	return nil
+}
+
+func setScalarSlice(v reflect.Value, raw string) error {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setValue(slice.Index(i), item); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+
+	return nil
+}
+
+func isScalar(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
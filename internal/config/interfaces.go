@@ -1,7 +1,18 @@
 package config
 
 import (
+	"time"
+
 	"github.com/IBM/sarama"
+
+	"github.com/major1ink/simple-notification-telegram/internal/binding"
+	"github.com/major1ink/simple-notification-telegram/internal/client/http/telegram"
+	"github.com/major1ink/simple-notification-telegram/internal/dedup"
+	"github.com/major1ink/simple-notification-telegram/internal/maintenance"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
+	telegramService "github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
 )
 
 type LoggerConfig interface {
@@ -13,15 +24,79 @@ type LoggerConfig interface {
 
 type KafkaConfig interface {
 	GetBrokers() []string
+	Apply(config *sarama.Config) error
 }
 
 type ConsumerConfig interface {
 	GetTopic() string
 	GetGroupId() string
-	Config() *sarama.Config
+	GetBindings() []binding.Config
+	GetDefaultBinding() binding.Config
+	GetBindingRules() []router.Rule
+	GetBindingTemplates() []telegramService.TemplateRule
+	GetTopicRefreshInterval() time.Duration
+	GetRetry() consumer.RetryConfig
+	Config() (*sarama.Config, error)
 }
 
 type TelegramConfig interface {
 	GetTelegramBotToken() string
 	GetTelegramChatID() int64
+	GetRateLimit() telegram.RateLimitConfig
+	GetMessageConfig() telegramService.Config
+	GetParseMode() model.ParseMode
+}
+
+type DeadLetterConfig interface {
+	GetEnabled() bool
+	GetTopic() string
+	Config() *sarama.Config
+}
+
+type DedupConfig interface {
+	GetEnabled() bool
+	GetKey() string
+	Config() dedup.Config
+}
+
+type JournalConfig interface {
+	GetEnabled() bool
+	GetPath() string
+	GetRetention() time.Duration
+	GetAdminEnabled() bool
+	GetAdminToken() string
+}
+
+type CorrelationConfig interface {
+	GetEnabled() bool
+	GetKey() string
+	GetMode() telegramService.CorrelationMode
+	GetTTL() time.Duration
+	GetPath() string
+}
+
+type HeldConfig interface {
+	GetPath() string
+}
+
+type MaintenanceConfig interface {
+	GetAdminEnabled() bool
+	GetAdminToken() string
+	GetWindows() []maintenance.Window
+}
+
+type RoutingConfig interface {
+	GetRules() []router.Rule
+	GetDefaultRoute() router.Route
+	GetHoldUsed() bool
+}
+
+type TemplatesConfig interface {
+	GetTemplates() telegramService.TemplateConfig
+}
+
+type HTTPServerConfig interface {
+	GetEnabled() bool
+	GetAddress() string
+	GetReadHeaderTimeout() time.Duration
 }
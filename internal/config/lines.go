@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lineIndex — номера строк YAML файла по пути к ключу (например, "kafkaConfig.brokers[1]")
+type lineIndex map[string]int
+
+func (l lineIndex) index(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			l.index(child, path)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			l[childPath] = key.Line
+			l.index(value, childPath)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			l[childPath] = child.Line
+			l.index(child, childPath)
+		}
+	}
+}
+
+// line возвращает номер строки ключа или ближайшего родителя, присутствующего в файле
+func (l lineIndex) line(path string) int {
+	for path != "" {
+		if line, ok := l[path]; ok {
+			return line
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return 0
+		}
+		path = path[:i]
+	}
+
+	return 0
+}
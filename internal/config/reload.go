@@ -0,0 +1,108 @@
+package config
+
+import (
+	"reflect"
+
+	structYaml "github.com/major1ink/simple-notification-telegram/internal/config/yaml"
+)
+
+// keepRestartSettings находит изменённые настройки, которые нельзя применить без
+// перезапуска (подключение к kafka, consumer group, дедупликация, журнал, корреляция, хранилище отложенных уведомлений, HTTP сервер, вывод логов, бот),
+// и оставляет в next их текущие значения, чтобы конфигурация не применялась частично.
+// Остальное — уровень логирования, маршрутизация и шаблоны (в том числе маршруты и шаблоны привязок topic),
+// лимиты, окна обслуживания — применяется на лету.
+func keepRestartSettings(prev, next *yamlConfig) []string {
+	var changed []string
+	keep := func(path string, current, updated any, restore func()) {
+		if !reflect.DeepEqual(current, updated) {
+			changed = append(changed, path)
+			restore()
+		}
+	}
+
+	keep("kafkaConfig", prev.Kafka, next.Kafka, func() { next.Kafka = prev.Kafka })
+	// Маршруты и шаблоны привязок применяются на лету, остальные параметры consumer — после перезапуска
+	keep("consumerConfig", withoutBindingRoutes(prev.Consumer), withoutBindingRoutes(next.Consumer), func() {
+		next.Consumer = keepBindingRoutes(prev.Consumer, next.Consumer)
+	})
+	keep("deadLetterConfig", prev.DeadLetter, next.DeadLetter, func() { next.DeadLetter = prev.DeadLetter })
+	keep("dedupConfig", prev.Dedup, next.Dedup, func() { next.Dedup = prev.Dedup })
+	keep("journalConfig", prev.Journal, next.Journal, func() { next.Journal = prev.Journal })
+	keep("correlationConfig", prev.Correlation, next.Correlation, func() { next.Correlation = prev.Correlation })
+	keep("heldConfig", prev.Held, next.Held, func() { next.Held = prev.Held })
+	keep("httpServer", prev.HTTPServer, next.HTTPServer, func() { next.HTTPServer = prev.HTTPServer })
+	keep("maintenance.admin_token", prev.Maintenance.AdminToken, next.Maintenance.AdminToken, func() {
+		next.Maintenance.AdminToken = prev.Maintenance.AdminToken
+	})
+
+	// Уровень логирования меняется на лету, остальные параметры логгера — нет
+	current, updated := *prev.Logger, *next.Logger
+	current.LogLevel, updated.LogLevel = "", ""
+	keep("logger", current, updated, func() {
+		logger := *prev.Logger
+		logger.LogLevel = next.Logger.LogLevel
+		next.Logger = &logger
+	})
+
+	if prev.Telegram != nil && next.Telegram != nil {
+		p, n := prev.Telegram, next.Telegram
+		keep("telegramConfig.telegram_bot_token", p.TelegramBotToken, n.TelegramBotToken, func() {
+			n.TelegramBotToken = p.TelegramBotToken
+		})
+		keep("telegramConfig.telegram_bot_token_file", p.TelegramBotTokenFile, n.TelegramBotTokenFile, func() {
+			n.TelegramBotTokenFile = p.TelegramBotTokenFile
+		})
+		keep("telegramConfig.parse_mode", p.ParseMode, n.ParseMode, func() { n.ParseMode = p.ParseMode })
+		keep("telegramConfig.long_message_mode", p.LongMessageMode, n.LongMessageMode, func() {
+			n.LongMessageMode = p.LongMessageMode
+		})
+		keep("telegramConfig.silent_below", p.SilentBelow, n.SilentBelow, func() { n.SilentBelow = p.SilentBelow })
+	}
+
+	return changed
+}
+
+// withoutBindingRoutes возвращает копию настроек consumer без маршрутов и шаблонов привязок
+func withoutBindingRoutes(c *structYaml.ConsumerConfig) *structYaml.ConsumerConfig {
+	if c == nil {
+		return nil
+	}
+
+	stripped := *c
+	stripped.Bindings = append([]structYaml.BindingConfig(nil), c.Bindings...)
+	for i := range stripped.Bindings {
+		stripped.Bindings[i].Template, stripped.Bindings[i].Route = "", nil
+	}
+	if c.DefaultBinding != nil {
+		def := *c.DefaultBinding
+		def.Template, def.Route = "", nil
+		stripped.DefaultBinding = &def
+	}
+
+	return &stripped
+}
+
+// keepBindingRoutes возвращает текущие настройки consumer с новыми маршрутами и шаблонами привязок.
+// Если изменился состав привязок, новые маршруты сопоставить не с чем, и сохраняются текущие
+func keepBindingRoutes(prev, next *structYaml.ConsumerConfig) *structYaml.ConsumerConfig {
+	if prev == nil || next == nil || len(prev.Bindings) != len(next.Bindings) ||
+		(prev.DefaultBinding == nil) != (next.DefaultBinding == nil) {
+		return prev
+	}
+
+	consumer := *prev
+	consumer.Bindings = append([]structYaml.BindingConfig(nil), prev.Bindings...)
+	for i := range consumer.Bindings {
+		if consumer.Bindings[i].GetName() != next.Bindings[i].GetName() {
+			return prev
+		}
+		consumer.Bindings[i].Template, consumer.Bindings[i].Route = next.Bindings[i].Template, next.Bindings[i].Route
+	}
+	if prev.DefaultBinding != nil {
+		def := *prev.DefaultBinding
+		def.Template, def.Route = next.DefaultBinding.Template, next.DefaultBinding.Route
+		consumer.DefaultBinding = &def
+	}
+
+	return &consumer
+}
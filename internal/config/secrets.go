@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	structYaml "github.com/major1ink/simple-notification-telegram/internal/config/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretProvider — источник значений для подстановок вида ${<scheme>:<ref>}
+type SecretProvider interface {
+	// Scheme — префикс подстановки, например "file" для ${file:/run/secrets/token}
+	Scheme() string
+	// Resolve возвращает значение секрета по ссылке
+	Resolve(ref string) (string, error)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file": fileSecretProvider{},
+		"env":  envSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider добавляет источник секретов. Должен вызываться до Load
+func RegisterSecretProvider(p SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+
+	secretProviders[p.Scheme()] = p
+}
+
+func secretProvider(scheme string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+
+	p, ok := secretProviders[scheme]
+	return p, ok
+}
+
+// fileSecretProvider читает секрет из файла (Docker/Kubernetes secrets)
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) Scheme() string {
+	return "file"
+}
+
+func (fileSecretProvider) Resolve(path string) (string, error) {
+	return readSecretFile(path)
+}
+
+// envSecretProvider берёт секрет из переменной окружения
+type envSecretProvider struct{}
+
+func (envSecretProvider) Scheme() string {
+	return "env"
+}
+
+func (envSecretProvider) Resolve(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return value, nil
+}
+
+// readSecretFile читает файл секрета без завершающего перевода строки
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// secretRefRe — подстановка ${scheme:ref}; $${...} оставляется как ${...}
+var secretRefRe = regexp.MustCompile(`\$?\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]*)\}`)
+
+// expandSecrets заменяет подстановки в строке значениями из источников секретов
+func expandSecrets(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var errs []string
+	result := secretRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+
+		m := secretRefRe.FindStringSubmatch(ref)
+		p, ok := secretProvider(m[1])
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown secret provider %q", m[1]))
+			return ref
+		}
+
+		value, err := p.Resolve(m[2])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to resolve ${%s:%s}: %v", m[1], m[2], err))
+			return ref
+		}
+
+		return value
+	})
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return result, nil
+}
+
+var secretType = reflect.TypeOf(structYaml.Secret(""))
+
+// resolveSecrets выполняет подстановки во всех строковых значениях конфигурации
+// и читает секреты из *_file ключей. Возвращает пути обычных строковых полей,
+// в которые были подставлены значения: они скрываются при выводе конфигурации
+func resolveSecrets(cfg *yamlConfig, lines lineIndex) (map[string]bool, error) {
+	var problems []string
+	report := func(path string, err error) {
+		problems = append(problems, Problem{Path: path, Line: lines.line(path), Message: err.Error()}.String())
+	}
+
+	interpolated := make(map[string]bool)
+	interpolateValue(reflect.ValueOf(cfg).Elem(), "", interpolated, report)
+
+	if t := cfg.Telegram; t != nil && t.TelegramBotTokenFile != "" {
+		if t.TelegramBotToken != "" {
+			report("telegramConfig.telegram_bot_token_file", fmt.Errorf("must not be set together with telegram_bot_token"))
+		} else if token, err := readSecretFile(t.TelegramBotTokenFile); err != nil {
+			report("telegramConfig.telegram_bot_token_file", err)
+		} else {
+			t.TelegramBotToken = structYaml.Secret(token)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("failed to resolve secrets:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return interpolated, nil
+}
+
+func interpolateValue(v reflect.Value, path string, interpolated map[string]bool, report func(string, error)) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			interpolateValue(v.Elem(), path, interpolated, report)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+
+			childPath := path
+			if tag != "" || !field.Anonymous {
+				if tag == "" {
+					tag = field.Name
+				}
+				childPath = joinPath(path, tag)
+			}
+			interpolateValue(v.Field(i), childPath, interpolated, report)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			interpolateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), interpolated, report)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			keyPath := joinPath(path, fmt.Sprint(iter.Key()))
+			value, err := expandSecrets(iter.Value().String())
+			if err != nil {
+				report(keyPath, err)
+				continue
+			}
+			if value != iter.Value().String() && v.Type().Elem() != secretType {
+				interpolated[keyPath] = true
+			}
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(value).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		value, err := expandSecrets(v.String())
+		if err != nil {
+			report(path, err)
+			return
+		}
+		if value != v.String() && v.Type() != secretType {
+			interpolated[path] = true
+		}
+		v.SetString(value)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// Dump возвращает загруженную конфигурацию в формате YAML со скрытыми секретами.
+// Значения, подставленные из ${...} в обычные строковые поля, тоже скрываются
+func Dump() (string, error) {
+	l := loaded.Load()
+	if l == nil {
+		return "", fmt.Errorf("config is not loaded")
+	}
+
+	var root yaml.Node
+	if err := root.Encode(l.raw); err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	redactPaths(&root, "", l.interpolated)
+
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return string(out), nil
+}
+
+// redactPaths заменяет на "***" скалярные значения по путям paths. Пути строятся так же, как в lineIndex
+func redactPaths(node *yaml.Node, path string, paths map[string]bool) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			redactPaths(child, path, paths)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			redactPaths(node.Content[i+1], joinPath(path, node.Content[i].Value), paths)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			redactPaths(child, fmt.Sprintf("%s[%d]", path, i), paths)
+		}
+	case yaml.ScalarNode:
+		if paths[path] {
+			node.SetString("***")
+		}
+	}
+}
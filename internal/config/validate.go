@@ -0,0 +1,742 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/major1ink/simple-notification-telegram/internal/binding"
+	structYaml "github.com/major1ink/simple-notification-telegram/internal/config/yaml"
+	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+	"github.com/major1ink/simple-notification-telegram/internal/dedup"
+	"github.com/major1ink/simple-notification-telegram/internal/eventkey"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
+	"github.com/major1ink/simple-notification-telegram/internal/schedule"
+	telegramService "github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+)
+
+// Problem — ошибка в значении ключа конфигурации
+type Problem struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", p.Path, p.Line, p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// ValidationError содержит все найденные ошибки конфигурации
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p.String())
+	}
+
+	return b.String()
+}
+
+var (
+	topicNameRe = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	botTokenRe  = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]+$`)
+)
+
+const maxTopicNameLength = 249
+
+type validator struct {
+	loaded   *loadedConfig
+	problems []Problem
+	warnings []Problem
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{
+		Path:    path,
+		Line:    v.loaded.lines.line(path),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// warn отмечает значение, которое допустимо, но скорее всего указано по ошибке
+func (v *validator) warn(path, format string, args ...any) {
+	v.warnings = append(v.warnings, Problem{
+		Path:    path,
+		Line:    v.loaded.lines.line(path),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate проверяет загруженную конфигурацию и возвращает *ValidationError
+// со всеми найденными ошибками
+func Validate() error {
+	l := loaded.Load()
+	if l == nil {
+		return fmt.Errorf("config is not loaded")
+	}
+
+	return validate(l)
+}
+
+// Warnings возвращает предупреждения, найденные при последней проверке загруженной конфигурации
+func Warnings() []Problem {
+	l := loaded.Load()
+	if l == nil {
+		return nil
+	}
+
+	return l.warnings
+}
+
+func validate(l *loadedConfig) error {
+	v := &validator{loaded: l}
+	cfg := l.raw
+
+	v.validateLogger(cfg.Logger)
+	v.validateKafka(cfg.Kafka)
+	v.validateConsumer(cfg.Consumer, cfg.Kafka)
+	v.validateTelegram(cfg.Telegram)
+	v.validateDeadLetter(cfg.DeadLetter)
+	v.validateDedup(cfg.Dedup)
+	v.validateJournal(cfg.Journal)
+	v.validateCorrelation(cfg.Correlation, cfg.Dedup)
+	v.validateStorePaths(cfg)
+	v.validateMaintenance(cfg.Maintenance)
+	v.validateRouting(cfg.Routing, cfg.Consumer)
+	v.validateTemplates(cfg.Templates, cfg.Telegram, cfg.Consumer)
+	v.validateHTTPServer(cfg.HTTPServer)
+
+	l.warnings = v.warnings
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+
+	return nil
+}
+
+func (v *validator) validateLogger(l *structYaml.LoggerConfig) {
+	switch strings.ToLower(l.LogLevel) {
+	case "", "debug", "info", "warn", "error", "fatal":
+	default:
+		v.add("logger.logLevel", "unknown log level %q, expected one of debug, info, warn, error, fatal", l.LogLevel)
+	}
+
+	switch l.LogMode {
+	case "", "stdout", "file":
+	default:
+		v.add("logger.logMode", "unknown log mode %q, expected stdout, file or empty", l.LogMode)
+	}
+}
+
+func (v *validator) validateKafka(k *structYaml.KafkaConfig) {
+	if k == nil {
+		v.add("kafkaConfig", "section is required")
+		return
+	}
+
+	if len(k.Brokers) == 0 {
+		v.add("kafkaConfig.brokers", "at least one broker is required")
+	}
+	for i, broker := range k.Brokers {
+		if msg := checkHostPort(broker, true); msg != "" {
+			v.add(fmt.Sprintf("kafkaConfig.brokers[%d]", i), "%q %s", broker, msg)
+		}
+	}
+
+	if k.Version != "" {
+		if _, err := sarama.ParseKafkaVersion(k.Version); err != nil {
+			v.add("kafkaConfig.version", "%s", err.Error())
+		}
+	}
+
+	if t := k.TLS; t != nil && t.Enabled {
+		if (t.CertFile == "") != (t.KeyFile == "") {
+			v.add("kafkaConfig.tls", "cert_file and key_file must be set together")
+		} else if _, err := t.Config(); err != nil {
+			v.add("kafkaConfig.tls", "%s", err.Error())
+		}
+	}
+
+	if s := k.SASL; s != nil && s.Mechanism != "" {
+		switch strings.ToUpper(s.Mechanism) {
+		case structYaml.SASLMechanismPlain, structYaml.SASLMechanismSCRAMSHA256, structYaml.SASLMechanismSCRAMSHA512:
+			if s.Username == "" {
+				v.add("kafkaConfig.sasl.username", "is required for mechanism %s", s.Mechanism)
+			}
+			if s.Password == "" {
+				v.add("kafkaConfig.sasl.password", "is required for mechanism %s", s.Mechanism)
+			}
+		case structYaml.SASLMechanismOAuthBearer:
+			if (s.Token == "") == (s.TokenFile == "") {
+				v.add("kafkaConfig.sasl.token", "exactly one of token or token_file is required for mechanism %s", s.Mechanism)
+			}
+		default:
+			v.add("kafkaConfig.sasl.mechanism", "unknown mechanism %q, expected PLAIN, SCRAM-SHA-256, SCRAM-SHA-512 or OAUTHBEARER", s.Mechanism)
+		}
+	}
+}
+
+func (v *validator) validateConsumer(c *structYaml.ConsumerConfig, k *structYaml.KafkaConfig) {
+	if c == nil {
+		v.add("consumerConfig", "section is required")
+		return
+	}
+
+	v.validateTopic("consumerConfig.topic", c.Topic, len(c.Bindings) == 0)
+	v.validateBindings(c)
+	if strings.TrimSpace(c.GroupId) == "" {
+		v.add("consumerConfig.group_id", "is required")
+	}
+
+	if r := c.Retry; r != nil {
+		if r.MaxAttempts < 0 {
+			v.add("consumerConfig.retry.max_attempts", "must not be negative")
+		}
+		if r.Multiplier != 0 && r.Multiplier < 1 {
+			v.add("consumerConfig.retry.multiplier", "must be >= 1")
+		}
+		if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+			v.add("consumerConfig.retry.jitter", "must be between 0 and 1")
+		}
+		if r.MaxInterval > 0 && r.InitialInterval > r.MaxInterval {
+			v.add("consumerConfig.retry.initial_interval", "must not exceed max_interval")
+		}
+	}
+
+	switch c.RebalanceStrategy {
+	case "", structYaml.RebalanceStrategyRange, structYaml.RebalanceStrategyRoundRobin, structYaml.RebalanceStrategySticky:
+	default:
+		v.add("consumerConfig.rebalance_strategy", "unknown strategy %q, expected range, round-robin or sticky", c.RebalanceStrategy)
+	}
+
+	switch c.InitialOffset {
+	case "", structYaml.InitialOffsetOldest, structYaml.InitialOffsetNewest:
+	default:
+		v.add("consumerConfig.initial_offset", "unknown offset %q, expected oldest or newest", c.InitialOffset)
+	}
+
+	if c.SessionTimeout < 0 {
+		v.add("consumerConfig.session_timeout", "must not be negative")
+	}
+	if c.HeartbeatInterval < 0 {
+		v.add("consumerConfig.heartbeat_interval", "must not be negative")
+	}
+	if c.MaxProcessingTime < 0 {
+		v.add("consumerConfig.max_processing_time", "must not be negative")
+	}
+	if f := c.Fetch; f != nil {
+		if f.Min < 0 || f.Default < 0 || f.Max < 0 {
+			v.add("consumerConfig.fetch", "sizes must not be negative")
+		}
+		if f.Max > 0 && f.Default > f.Max {
+			v.add("consumerConfig.fetch.default", "must not exceed fetch.max")
+		}
+	}
+
+	// Неизвестная стратегия уже отмечена выше
+	config, err := c.Config()
+	if err != nil {
+		return
+	}
+	if k != nil {
+		if version, err := sarama.ParseKafkaVersion(k.Version); k.Version != "" && err == nil {
+			config.Version = version
+		}
+	}
+	if config.Consumer.Group.Heartbeat.Interval*3 > config.Consumer.Group.Session.Timeout {
+		v.add("consumerConfig.heartbeat_interval", "must be at most a third of session_timeout (%s)", config.Consumer.Group.Session.Timeout)
+	} else if err := config.Validate(); err != nil {
+		v.add("consumerConfig", "%s", err.Error())
+	}
+}
+
+func (v *validator) validateTelegram(t *structYaml.TelegramConfig) {
+	if t == nil {
+		v.add("telegramConfig", "section is required")
+		return
+	}
+
+	switch {
+	case strings.TrimSpace(t.TelegramBotToken.Value()) == "":
+		v.add("telegramConfig.telegram_bot_token", "is required (or set telegram_bot_token_file)")
+	case !botTokenRe.MatchString(t.TelegramBotToken.Value()):
+		v.add("telegramConfig.telegram_bot_token", "must have the form <bot id>:<secret>")
+	}
+
+	// Без routing.default telegram_chat_id обязателен
+	if v.loaded.defaultRouteFromChatID || t.TelegramChatID != 0 {
+		v.checkChatID("telegramConfig.telegram_chat_id", t.TelegramChatID)
+	}
+
+	switch strings.ToLower(t.ParseMode) {
+	case "", "html", "markdown", "markdownv2", "plain", "none", "text":
+	default:
+		v.add("telegramConfig.parse_mode", "unknown parse mode %q, expected HTML, MarkdownV2, Markdown or plain", t.ParseMode)
+	}
+
+	switch telegramService.LongMessageMode(t.LongMessageMode) {
+	case "", telegramService.LongMessageSplit, telegramService.LongMessageDocument:
+	default:
+		v.add("telegramConfig.long_message_mode", "unknown mode %q, expected split or document", t.LongMessageMode)
+	}
+
+	v.validateSeverity("telegramConfig.silent_below", t.SilentBelow)
+
+	if r := t.RateLimit; r != nil {
+		if r.GlobalPerSecond < 0 {
+			v.add("telegramConfig.rate_limit.global_per_second", "must not be negative")
+		}
+		if r.PerChatPerMinute < 0 {
+			v.add("telegramConfig.rate_limit.per_chat_per_minute", "must not be negative")
+		}
+		if r.PrivatePerSecond < 0 {
+			v.add("telegramConfig.rate_limit.private_per_second", "must not be negative")
+		}
+		if r.GlobalBurst < 0 {
+			v.add("telegramConfig.rate_limit.global_burst", "must not be negative")
+		}
+		if r.PerChatBurst < 0 {
+			v.add("telegramConfig.rate_limit.per_chat_burst", "must not be negative")
+		}
+		if r.MaxRetryAfterAttempts != nil && *r.MaxRetryAfterAttempts < 0 {
+			v.add("telegramConfig.rate_limit.max_retry_after_attempts", "must not be negative")
+		}
+	}
+}
+
+func (v *validator) validateDeadLetter(d *structYaml.DeadLetterConfig) {
+	if d.Topic != "" {
+		v.validateTopic("deadLetterConfig.topic", d.Topic, false)
+	}
+}
+
+func (v *validator) validateDedup(d *structYaml.DedupConfig) {
+	switch d.Backend {
+	case "", dedup.BackendMemory, dedup.BackendBolt:
+	default:
+		v.add("dedupConfig.backend", "unknown backend %q, expected %s or %s", d.Backend, dedup.BackendMemory, dedup.BackendBolt)
+	}
+
+	if _, err := eventkey.New(d.Key); err != nil {
+		v.add("dedupConfig.key", "%s", err.Error())
+	}
+	if d.TTL < 0 {
+		v.add("dedupConfig.ttl", "must not be negative")
+	}
+	if d.MaxEntries < 0 {
+		v.add("dedupConfig.max_entries", "must not be negative")
+	}
+}
+
+func (v *validator) validateJournal(j *structYaml.JournalConfig) {
+	if j.Retention < 0 {
+		v.add("journalConfig.retention", "must not be negative")
+	}
+}
+
+func (v *validator) validateCorrelation(c *structYaml.CorrelationConfig, d *structYaml.DedupConfig) {
+	if c.Key != "" {
+		if _, err := eventkey.New(c.Key); err != nil {
+			v.add("correlationConfig.key", "%s", err.Error())
+		}
+	}
+	// С одинаковым ключом последующие события инцидента отбрасывались бы дедупликацией
+	if c.Key != "" && d.GetEnabled() && c.Key == d.GetKey() {
+		v.add("correlationConfig.key", "must differ from dedupConfig.key %q, otherwise follow-up events of an incident are dropped as duplicates", d.GetKey())
+	}
+
+	switch telegramService.CorrelationMode(c.Mode) {
+	case "", telegramService.CorrelationEdit, telegramService.CorrelationReply:
+	default:
+		v.add("correlationConfig.mode", "unknown mode %q, expected %s or %s",
+			c.Mode, telegramService.CorrelationEdit, telegramService.CorrelationReply)
+	}
+
+	if c.TTL < 0 {
+		v.add("correlationConfig.ttl", "must not be negative")
+	}
+}
+
+// validateStorePaths проверяет, что файлы bbolt не совпадают: база блокируется одним открытием
+func (v *validator) validateStorePaths(cfg yamlConfig) {
+	paths := map[string]string{}
+	check := func(path, file string) {
+		if file == "" {
+			return
+		}
+		file = filepath.Clean(file)
+		if other, ok := paths[file]; ok {
+			v.add(path, "must differ from %s", other)
+			return
+		}
+		paths[file] = path
+	}
+
+	if cfg.Dedup.Backend == dedup.BackendBolt {
+		file := cfg.Dedup.Path
+		if file == "" {
+			file = dedup.DefaultPath
+		}
+		check("dedupConfig.path", file)
+	}
+	check("journalConfig.path", cfg.Journal.Path)
+	if cfg.Correlation.Key != "" {
+		check("correlationConfig.path", cfg.Correlation.Path)
+	}
+	if cfg.Routing.GetHoldUsed() {
+		check("heldConfig.path", cfg.Held.GetPath())
+	}
+}
+
+func (v *validator) validateBindings(c *structYaml.ConsumerConfig) {
+	names := map[string]bool{binding.DefaultName: true}
+	for i, b := range c.Bindings {
+		path := fmt.Sprintf("consumerConfig.bindings[%d]", i)
+
+		switch {
+		case b.Topic == "" && b.Pattern == "":
+			v.add(path, "topic or pattern is required")
+		case b.Topic != "" && b.Pattern != "":
+			v.add(path, "topic and pattern are mutually exclusive")
+		case b.Topic != "":
+			v.validateTopic(path+".topic", b.Topic, true)
+		default:
+			if _, err := binding.Compile(b.Pattern); err != nil {
+				v.add(path+".pattern", "%s", err.Error())
+			}
+		}
+
+		name := b.GetName()
+		if names[name] {
+			v.add(path+".name", "duplicate binding name %q", name)
+		}
+		names[name] = true
+
+		v.validateDecoder(path, b)
+		if b.Route != nil {
+			v.validateRoute(path+".route", *b.Route)
+		}
+	}
+
+	if def := c.DefaultBinding; def != nil {
+		if def.Topic != "" || def.Pattern != "" || def.Name != "" {
+			v.add("consumerConfig.default_binding", "name, topic and pattern are not allowed in the default binding")
+		}
+		v.validateDecoder("consumerConfig.default_binding", *def)
+		if def.Route != nil {
+			v.validateRoute("consumerConfig.default_binding.route", *def.Route)
+		}
+	}
+}
+
+func (v *validator) validateDecoder(path string, b structYaml.BindingConfig) {
+	switch b.Decoder {
+	case "", decoder.NameAssembled:
+	case decoder.NameProtobuf:
+		v.validateProtobuf(path+".protobuf", b.Protobuf)
+	case decoder.NameAvro:
+		v.validateAvro(path+".avro", b.Avro)
+	case decoder.NameCloudEvents:
+		if b.CloudEvents != nil {
+			v.validateFieldMapping(path+".cloudevents.fields", b.CloudEvents.Fields)
+		}
+	case decoder.NameJSON:
+		v.validateJSON(path+".json", b.JSON)
+	default:
+		v.add(path+".decoder", "unknown decoder %q, expected %s, %s, %s, %s or %s", b.Decoder,
+			decoder.NameAssembled, decoder.NameJSON, decoder.NameProtobuf, decoder.NameAvro, decoder.NameCloudEvents)
+	}
+}
+
+func (v *validator) validateJSON(path string, j *structYaml.JSONConfig) {
+	if j == nil {
+		return
+	}
+
+	v.validateFieldMapping(path+".fields", j.Fields)
+	for name, fieldPath := range j.Extra {
+		if name == "" {
+			v.add(path+".extra", "field name must not be empty")
+		}
+		if _, err := decoder.ParsePath(fieldPath); err != nil {
+			v.add(path+".extra."+name, "%s", err.Error())
+		}
+	}
+}
+
+// validateFieldMapping проверяет синтаксис путей к полям сообщения
+func (v *validator) validateFieldMapping(path string, f *structYaml.FieldMappingConfig) {
+	if f == nil {
+		return
+	}
+
+	for _, field := range []struct{ name, path string }{
+		{"event_uuid", f.EventUuid},
+		{"type_event", f.TypeEvent},
+		{"app", f.App},
+		{"message", f.Message},
+		{"severity", f.Severity},
+	} {
+		if field.path == "" {
+			continue
+		}
+		if _, err := decoder.ParsePath(field.path); err != nil {
+			v.add(path+"."+field.name, "%s", err.Error())
+		}
+	}
+}
+
+func (v *validator) validateProtobuf(path string, p *structYaml.ProtobufConfig) {
+	switch {
+	case p == nil:
+		v.add(path, "section is required for decoder %s", decoder.NameProtobuf)
+	case p.DescriptorSet == "":
+		v.add(path+".descriptor_set", "is required")
+	case p.MessageType == "" && p.TypeHeader == "":
+		v.add(path+".message_type", "message_type or type_header is required")
+	default:
+		if _, err := decoder.NewProtobufDecoder(p.Config()); err != nil {
+			v.add(path, "%s", err.Error())
+		}
+	}
+
+	if p != nil {
+		v.validateFieldMapping(path+".fields", p.Fields)
+	}
+}
+
+func (v *validator) validateAvro(path string, a *structYaml.AvroConfig) {
+	switch {
+	case a == nil:
+		v.add(path, "section is required for decoder %s", decoder.NameAvro)
+		return
+	case a.RegistryURL == "" && a.SchemaDir == "":
+		v.add(path, "registry_url or schema_dir is required")
+	case a.RegistryURL != "" && a.SchemaDir != "":
+		v.add(path, "registry_url and schema_dir are mutually exclusive")
+	case a.RegistryURL != "":
+		if u, err := url.Parse(a.RegistryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add(path+".registry_url", "must be an http(s) URL, got %q", a.RegistryURL)
+		}
+	default:
+		if _, err := decoder.NewDirSchemaSource(a.SchemaDir); err != nil {
+			v.add(path+".schema_dir", "%s", err.Error())
+		}
+	}
+
+	if a.RegistryTimeout < 0 {
+		v.add(path+".registry_timeout", "must not be negative")
+	}
+	if a.RegistryPassword != "" && a.RegistryUsername == "" {
+		v.add(path+".registry_username", "is required when registry_password is set")
+	}
+	v.validateFieldMapping(path+".fields", a.Fields)
+}
+
+func (v *validator) validateMaintenance(m *structYaml.MaintenanceConfig) {
+	for i, window := range m.Windows {
+		path := fmt.Sprintf("maintenance.windows[%d]", i)
+		if window.App == "" {
+			v.add(path+".app", "is required")
+		}
+		if window.Until.IsZero() {
+			v.add(path+".until", "is required")
+		}
+	}
+}
+
+func (v *validator) validateRouting(r *structYaml.RoutingConfig, c *structYaml.ConsumerConfig) {
+	if r.Default != nil && !v.loaded.defaultRouteFromChatID {
+		v.validateRoute("routing.default", *r.Default)
+	}
+	if r.Default == nil {
+		v.add("routing.default", "default route is required when telegramConfig.telegram_chat_id is not set")
+	}
+
+	for i, rule := range r.Rules {
+		path := fmt.Sprintf("routing.rules[%d]", i)
+		if len(rule.Match) == 0 {
+			v.add(path+".match", "at least one condition is required")
+		}
+		v.validateRoute(path, rule.RouteConfig)
+	}
+
+	rules := r.GetRules()
+	if c != nil {
+		rules = append(c.GetBindingRules(), rules...)
+	}
+	if _, err := router.New(rules, r.GetDefaultRoute()); err != nil {
+		v.add("routing.rules", "%s", err.Error())
+	}
+}
+
+func (v *validator) validateRoute(path string, route structYaml.RouteConfig) {
+	switch router.Action(route.Action) {
+	case "", router.ActionSend:
+		if len(route.ChatIDs) == 0 {
+			v.add(path+".chat_ids", "at least one chat is required for action send")
+		}
+	case router.ActionDrop:
+	default:
+		v.add(path+".action", "unknown action %q, expected send or drop", route.Action)
+	}
+
+	for i, chatID := range route.ChatIDs {
+		v.checkChatID(fmt.Sprintf("%s.chat_ids[%d]", path, i), chatID)
+	}
+	if route.ThreadID < 0 {
+		v.add(path+".thread_id", "must not be negative")
+	}
+	v.validateSeverity(path+".min_severity", route.MinSeverity)
+	if route.QuietHours != nil {
+		v.validateQuietHours(path+".quiet_hours", route.QuietHours)
+	}
+}
+
+func (v *validator) validateQuietHours(path string, q *structYaml.QuietHoursConfig) {
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		v.add(path+".timezone", "unknown time zone %q", q.Timezone)
+	}
+	if len(q.Periods) == 0 {
+		v.add(path+".periods", "at least one period is required")
+	}
+
+	for i, period := range q.Periods {
+		periodPath := fmt.Sprintf("%s.periods[%d]", path, i)
+		for j, day := range period.Days {
+			if _, err := schedule.ParseWeekday(day); err != nil {
+				v.add(fmt.Sprintf("%s.days[%d]", periodPath, j), "%s, expected mon, tue, wed, thu, fri, sat or sun", err.Error())
+			}
+		}
+
+		from, errFrom := schedule.ParseClock(period.From)
+		if errFrom != nil {
+			v.add(periodPath+".from", "%s", errFrom.Error())
+		}
+		to, errTo := schedule.ParseClock(period.To)
+		if errTo != nil {
+			v.add(periodPath+".to", "%s", errTo.Error())
+		}
+		if errFrom == nil && errTo == nil && from == to {
+			v.add(periodPath+".to", "must differ from from, use 00:00 and 24:00 for the whole day")
+		}
+	}
+
+	v.validateSeverity(path+".below", q.Below)
+
+	switch router.QuietAction(q.Action) {
+	case "", router.QuietSilent, router.QuietHold, router.QuietDrop:
+	default:
+		v.add(path+".action", "unknown action %q, expected silent, hold or drop", q.Action)
+	}
+}
+
+// validateSeverity проверяет имя уровня важности; пустое значение допустимо
+func (v *validator) validateSeverity(path, value string) {
+	if value == "" {
+		return
+	}
+	if _, ok := model.ParseSeverity(value); !ok {
+		v.add(path, "unknown severity %q, expected debug, info, warning, error or critical", value)
+	}
+}
+
+func (v *validator) validateTemplates(t *structYaml.TemplatesConfig, telegram *structYaml.TelegramConfig, c *structYaml.ConsumerConfig) {
+	for name := range t.SeverityPrefixes {
+		if name == "" {
+			v.add("templates.severity_prefixes", "severity must not be empty")
+			continue
+		}
+		v.validateSeverity("templates.severity_prefixes."+name, name)
+	}
+
+	cfg := t.GetTemplates()
+	if telegram != nil {
+		cfg.ParseMode = telegram.GetParseMode()
+	}
+	if c != nil {
+		cfg.Rules = append(c.GetBindingTemplates(), cfg.Rules...)
+	}
+
+	if _, err := telegramService.NewRenderer(cfg); err != nil {
+		v.add("templates", "%s", err.Error())
+	}
+}
+
+func (v *validator) validateHTTPServer(h *structYaml.HTTPServerConfig) {
+	if h.Address == "" {
+		return
+	}
+	if msg := checkHostPort(h.Address, false); msg != "" {
+		v.add("httpServer.address", "%q %s", h.Address, msg)
+	}
+}
+
+func (v *validator) validateTopic(path, topic string, required bool) {
+	switch {
+	case topic == "":
+		if required {
+			v.add(path, "is required")
+		}
+	case topic == "." || topic == "..":
+		v.add(path, "topic name %q is not allowed", topic)
+	case len(topic) > maxTopicNameLength:
+		v.add(path, "topic name is longer than %d characters", maxTopicNameLength)
+	case !topicNameRe.MatchString(topic):
+		v.add(path, "topic name %q may contain only ASCII letters, digits, '.', '_' and '-'", topic)
+	}
+}
+
+// checkHostPort проверяет адрес вида host:port
+func checkHostPort(addr string, hostRequired bool) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "is not a valid host:port: " + err.Error()
+	}
+	if hostRequired && host == "" {
+		return "is not a valid host:port: missing host"
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "is not a valid host:port: port must be a number between 1 and 65535"
+	}
+
+	return ""
+}
+
+// checkChatID проверяет идентификатор чата Telegram. Идентификаторы пользователей
+// положительные, групп — отрицательные, супергрупп и каналов — вида -100XXXXXXXXXX.
+// Короткий идентификатор, начинающийся с -100, может принадлежать обычной группе,
+// поэтому о нём только предупреждаем.
+func (v *validator) checkChatID(path string, id int64) {
+	if id == 0 {
+		v.add(path, "chat id is required")
+		return
+	}
+
+	s := strconv.FormatInt(id, 10)
+	if id > 0 && strings.HasPrefix(s, "100") && len(s) >= 13 {
+		v.add(path, "chat id %d looks like a supergroup or channel id without the leading '-'", id)
+	}
+	if strings.HasPrefix(s, "-100") && len(s) > 4 && len(s) < 14 {
+		v.warn(path, "chat id %d is too short for a supergroup or channel id (-100 followed by 10+ digits), check it is a basic group id", id)
+	}
+}
@@ -0,0 +1,224 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/binding"
+	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
+	"github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+)
+
+type BindingConfig struct {
+	Name           string       `yaml:"name"`
+	Topic          string       `yaml:"topic"`
+	Pattern        string       `yaml:"pattern"`
+	Decoder        string       `yaml:"decoder"`
+	Template       string       `yaml:"template"`
+	Route          *RouteConfig `yaml:"route"`
+	SeverityHeader string       `yaml:"severity_header"`
+
+	Protobuf    *ProtobufConfig    `yaml:"protobuf"`
+	Avro        *AvroConfig        `yaml:"avro"`
+	CloudEvents *CloudEventsConfig `yaml:"cloudevents"`
+	JSON        *JSONConfig        `yaml:"json"`
+}
+
+type ProtobufConfig struct {
+	DescriptorSet string              `yaml:"descriptor_set"`
+	MessageType   string              `yaml:"message_type"`
+	TypeHeader    string              `yaml:"type_header"`
+	Fields        *FieldMappingConfig `yaml:"fields"`
+}
+
+type AvroConfig struct {
+	RegistryURL      string              `yaml:"registry_url"`
+	RegistryUsername string              `yaml:"registry_username"`
+	RegistryPassword Secret              `yaml:"registry_password"`
+	RegistryTimeout  time.Duration       `yaml:"registry_timeout"`
+	SchemaDir        string              `yaml:"schema_dir"`
+	Fields           *FieldMappingConfig `yaml:"fields"`
+}
+
+type CloudEventsConfig struct {
+	Fields *FieldMappingConfig `yaml:"fields"`
+}
+
+type JSONConfig struct {
+	Fields *FieldMappingConfig `yaml:"fields"`
+	// Extra — дополнительные значения для шаблонов (.Fields): имя → путь в документе
+	Extra map[string]string `yaml:"extra"`
+}
+
+// FieldMappingConfig — пути к полям сообщения для полей события
+type FieldMappingConfig struct {
+	EventUuid string `yaml:"event_uuid"`
+	TypeEvent string `yaml:"type_event"`
+	App       string `yaml:"app"`
+	Message   string `yaml:"message"`
+	Severity  string `yaml:"severity"`
+}
+
+func (f *FieldMappingConfig) mapping() decoder.FieldMapping {
+	if f == nil {
+		return decoder.FieldMapping{}
+	}
+
+	return decoder.FieldMapping{
+		EventUuid: f.EventUuid,
+		TypeEvent: f.TypeEvent,
+		App:       f.App,
+		Message:   f.Message,
+		Severity:  f.Severity,
+	}
+}
+
+func (p *ProtobufConfig) Config() decoder.ProtobufConfig {
+	if p == nil {
+		return decoder.ProtobufConfig{}
+	}
+
+	return decoder.ProtobufConfig{
+		DescriptorSet: p.DescriptorSet,
+		MessageType:   p.MessageType,
+		TypeHeader:    p.TypeHeader,
+		Fields:        p.Fields.mapping(),
+	}
+}
+
+func (a *AvroConfig) Config() decoder.AvroConfig {
+	if a == nil {
+		return decoder.AvroConfig{}
+	}
+
+	return decoder.AvroConfig{
+		Registry: decoder.RegistryConfig{
+			URL:      a.RegistryURL,
+			Username: a.RegistryUsername,
+			Password: a.RegistryPassword.Value(),
+			Timeout:  a.RegistryTimeout,
+		},
+		SchemaDir: a.SchemaDir,
+		Fields:    a.Fields.mapping(),
+	}
+}
+
+func (c *CloudEventsConfig) Config() decoder.CloudEventsConfig {
+	if c == nil {
+		return decoder.CloudEventsConfig{}
+	}
+
+	return decoder.CloudEventsConfig{Fields: c.Fields.mapping()}
+}
+
+func (j *JSONConfig) Config() decoder.JSONConfig {
+	if j == nil {
+		return decoder.JSONConfig{}
+	}
+
+	return decoder.JSONConfig{
+		Fields: j.Fields.mapping(),
+		Extra:  j.Extra,
+	}
+}
+
+// GetName возвращает имя привязки: явно заданное, имя topic или шаблон
+func (b BindingConfig) GetName() string {
+	switch {
+	case b.Name != "":
+		return b.Name
+	case b.Topic != "":
+		return b.Topic
+	default:
+		return b.Pattern
+	}
+}
+
+func (b BindingConfig) config() binding.Config {
+	return binding.Config{
+		Name:           b.GetName(),
+		Topic:          b.Topic,
+		Pattern:        b.Pattern,
+		Decoder:        b.Decoder,
+		SeverityHeader: b.SeverityHeader,
+		Protobuf:       b.Protobuf.Config(),
+		Avro:           b.Avro.Config(),
+		CloudEvents:    b.CloudEvents.Config(),
+		JSON:           b.JSON.Config(),
+	}
+}
+
+func (c *ConsumerConfig) GetBindings() []binding.Config {
+	bindings := make([]binding.Config, 0, len(c.Bindings))
+	for _, b := range c.Bindings {
+		bindings = append(bindings, b.config())
+	}
+
+	return bindings
+}
+
+// GetDefaultBinding возвращает привязку для topic из consumerConfig.topic
+// и topic, не попавших ни под одну привязку
+func (c *ConsumerConfig) GetDefaultBinding() binding.Config {
+	if c.DefaultBinding == nil {
+		return binding.Config{Name: binding.DefaultName, Topic: c.Topic}
+	}
+
+	def := c.DefaultBinding.config()
+	def.Name = binding.DefaultName
+	def.Topic = c.Topic
+	def.Pattern = ""
+
+	return def
+}
+
+// GetBindingRules возвращает правила маршрутизации, заданные в привязках.
+// Они проверяются раньше правил из секции routing.
+func (c *ConsumerConfig) GetBindingRules() []router.Rule {
+	var rules []router.Rule
+	for _, b := range c.allBindings() {
+		if b.Route == nil {
+			continue
+		}
+
+		rules = append(rules, router.Rule{
+			Name: "binding:" + b.GetName(),
+			Conditions: []router.Condition{{
+				Field: router.FieldBinding,
+				Type:  router.MatchExact,
+				Value: b.GetName(),
+			}},
+			Route: b.Route.route(),
+		})
+	}
+
+	return rules
+}
+
+// GetBindingTemplates возвращает правила выбора шаблонов, заданные в привязках
+func (c *ConsumerConfig) GetBindingTemplates() []telegram.TemplateRule {
+	var rules []telegram.TemplateRule
+	for _, b := range c.allBindings() {
+		if b.Template == "" {
+			continue
+		}
+
+		rules = append(rules, telegram.TemplateRule{
+			Binding:  b.GetName(),
+			Template: b.Template,
+		})
+	}
+
+	return rules
+}
+
+func (c *ConsumerConfig) allBindings() []BindingConfig {
+	bindings := c.Bindings
+	if c.DefaultBinding != nil {
+		def := *c.DefaultBinding
+		def.Name = binding.DefaultName
+		bindings = append(bindings[:len(bindings):len(bindings)], def)
+	}
+
+	return bindings
+}
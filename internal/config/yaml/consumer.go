@@ -1,12 +1,58 @@
 package yaml
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/IBM/sarama"
+
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// Стратегии распределения партиций
+const (
+	RebalanceStrategyRange      = "range"
+	RebalanceStrategyRoundRobin = "round-robin"
+	RebalanceStrategySticky     = "sticky"
+)
+
+// Начальное смещение новой consumer group
+const (
+	InitialOffsetOldest = "oldest"
+	InitialOffsetNewest = "newest"
 )
 
 type ConsumerConfig struct {
-	Topic   string `yaml:"topic"`
-	GroupId string `yaml:"group_id"`
+	Topic             string        `yaml:"topic"`
+	GroupId           string        `yaml:"group_id"`
+	Retry             *RetryConfig  `yaml:"retry"`
+	RebalanceStrategy string        `yaml:"rebalance_strategy"`
+	InitialOffset     string        `yaml:"initial_offset"`
+	SessionTimeout    time.Duration `yaml:"session_timeout"`
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+	MaxProcessingTime time.Duration `yaml:"max_processing_time"`
+	Fetch             *FetchConfig  `yaml:"fetch"`
+	RackID            string        `yaml:"rack_id"`
+
+	Bindings             []BindingConfig `yaml:"bindings"`
+	DefaultBinding       *BindingConfig  `yaml:"default_binding"`
+	TopicRefreshInterval time.Duration   `yaml:"topic_refresh_interval"`
+}
+
+// FetchConfig — размеры запросов fetch в байтах
+type FetchConfig struct {
+	Min     int32 `yaml:"min"`
+	Default int32 `yaml:"default"`
+	Max     int32 `yaml:"max"`
+}
+
+type RetryConfig struct {
+	MaxAttempts     int           `yaml:"max_attempts"`
+	InitialInterval time.Duration `yaml:"initial_interval"`
+	MaxInterval     time.Duration `yaml:"max_interval"`
+	Multiplier      float64       `yaml:"multiplier"`
+	Jitter          *float64      `yaml:"jitter"`
+	MaxElapsedTime  time.Duration `yaml:"max_elapsed_time"`
 }
 
 func (c *ConsumerConfig) GetTopic() string {
@@ -17,11 +63,93 @@ func (c *ConsumerConfig) GetGroupId() string {
 	return c.GroupId
 }
 
-func (с *ConsumerConfig) Config() *sarama.Config {
+// GetTopicRefreshInterval возвращает интервал поиска новых topic по шаблонам привязок
+func (c *ConsumerConfig) GetTopicRefreshInterval() time.Duration {
+	if c.TopicRefreshInterval <= 0 {
+		return time.Minute
+	}
+
+	return c.TopicRefreshInterval
+}
+
+func (c *ConsumerConfig) GetRetry() consumer.RetryConfig {
+	retry := consumer.DefaultRetryConfig()
+	if c.Retry == nil {
+		return retry
+	}
+
+	if c.Retry.MaxAttempts > 0 {
+		retry.MaxAttempts = c.Retry.MaxAttempts
+	}
+	if c.Retry.InitialInterval > 0 {
+		retry.InitialInterval = c.Retry.InitialInterval
+	}
+	if c.Retry.MaxInterval > 0 {
+		retry.MaxInterval = c.Retry.MaxInterval
+	}
+	if c.Retry.Multiplier > 0 {
+		retry.Multiplier = c.Retry.Multiplier
+	}
+	if c.Retry.Jitter != nil {
+		retry.Jitter = *c.Retry.Jitter
+	}
+	if c.Retry.MaxElapsedTime > 0 {
+		retry.MaxElapsedTime = c.Retry.MaxElapsedTime
+	}
+
+	return retry
+}
+
+func (с *ConsumerConfig) Config() (*sarama.Config, error) {
+	strategy, err := с.balanceStrategy()
+	if err != nil {
+		return nil, err
+	}
+
 	config := sarama.NewConfig()
 	config.Version = sarama.V4_0_0_0
-	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{strategy}
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if с.InitialOffset == InitialOffsetNewest {
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+
+	if с.SessionTimeout > 0 {
+		config.Consumer.Group.Session.Timeout = с.SessionTimeout
+	}
+	if с.HeartbeatInterval > 0 {
+		config.Consumer.Group.Heartbeat.Interval = с.HeartbeatInterval
+	}
+	if с.MaxProcessingTime > 0 {
+		config.Consumer.MaxProcessingTime = с.MaxProcessingTime
+	}
+	if с.Fetch != nil {
+		if с.Fetch.Min > 0 {
+			config.Consumer.Fetch.Min = с.Fetch.Min
+		}
+		if с.Fetch.Default > 0 {
+			config.Consumer.Fetch.Default = с.Fetch.Default
+		}
+		if с.Fetch.Max > 0 {
+			config.Consumer.Fetch.Max = с.Fetch.Max
+		}
+	}
+	config.RackID = с.RackID
+
+	return config, nil
+}
 
-	return config
+// balanceStrategy возвращает стратегию распределения партиций. Кооперативные стратегии
+// требуют инкрементальной ребалансировки, которую sarama не поддерживает
+func (с *ConsumerConfig) balanceStrategy() (sarama.BalanceStrategy, error) {
+	switch с.RebalanceStrategy {
+	case "", RebalanceStrategyRoundRobin:
+		return sarama.NewBalanceStrategyRoundRobin(), nil
+	case RebalanceStrategyRange:
+		return sarama.NewBalanceStrategyRange(), nil
+	case RebalanceStrategySticky:
+		return sarama.NewBalanceStrategySticky(), nil
+	default:
+		return nil, fmt.Errorf("unknown rebalance strategy %q", с.RebalanceStrategy)
+	}
 }
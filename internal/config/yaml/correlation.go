@@ -0,0 +1,38 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+)
+
+type CorrelationConfig struct {
+	Key  string        `yaml:"key"`
+	Mode string        `yaml:"mode"`
+	TTL  time.Duration `yaml:"ttl"`
+	Path string        `yaml:"path"`
+}
+
+func (c *CorrelationConfig) GetEnabled() bool {
+	return c.Key != ""
+}
+
+func (c *CorrelationConfig) GetKey() string {
+	return c.Key
+}
+
+func (c *CorrelationConfig) GetMode() telegram.CorrelationMode {
+	if c.Mode == "" {
+		return telegram.CorrelationEdit
+	}
+
+	return telegram.CorrelationMode(c.Mode)
+}
+
+func (c *CorrelationConfig) GetTTL() time.Duration {
+	return c.TTL
+}
+
+func (c *CorrelationConfig) GetPath() string {
+	return c.Path
+}
@@ -0,0 +1,26 @@
+package yaml
+
+import (
+	"github.com/IBM/sarama"
+)
+
+type DeadLetterConfig struct {
+	Topic string `yaml:"topic"`
+}
+
+func (d *DeadLetterConfig) GetEnabled() bool {
+	return d.Topic != ""
+}
+
+func (d *DeadLetterConfig) GetTopic() string {
+	return d.Topic
+}
+
+func (d *DeadLetterConfig) Config() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = sarama.V4_0_0_0
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+
+	return config
+}
@@ -0,0 +1,37 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/dedup"
+	"github.com/major1ink/simple-notification-telegram/internal/eventkey"
+)
+
+type DedupConfig struct {
+	Backend    string        `yaml:"backend"`
+	Key        string        `yaml:"key"`
+	TTL        time.Duration `yaml:"ttl"`
+	MaxEntries int           `yaml:"max_entries"`
+	Path       string        `yaml:"path"`
+}
+
+func (d *DedupConfig) GetEnabled() bool {
+	return d.Backend != ""
+}
+
+func (d *DedupConfig) GetKey() string {
+	if d.Key == "" {
+		return eventkey.FieldEventUuid
+	}
+
+	return d.Key
+}
+
+func (d *DedupConfig) Config() dedup.Config {
+	return dedup.Config{
+		Backend:    d.Backend,
+		TTL:        d.TTL,
+		MaxEntries: d.MaxEntries,
+		Path:       d.Path,
+	}
+}
@@ -0,0 +1,17 @@
+package yaml
+
+import (
+	"github.com/major1ink/simple-notification-telegram/internal/held"
+)
+
+type HeldConfig struct {
+	Path string `yaml:"path"`
+}
+
+func (h *HeldConfig) GetPath() string {
+	if h.Path == "" {
+		return held.DefaultPath
+	}
+
+	return h.Path
+}
@@ -0,0 +1,24 @@
+package yaml
+
+import "time"
+
+type HTTPServerConfig struct {
+	Address           string        `yaml:"address"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+}
+
+func (h *HTTPServerConfig) GetEnabled() bool {
+	return h.Address != ""
+}
+
+func (h *HTTPServerConfig) GetAddress() string {
+	return h.Address
+}
+
+func (h *HTTPServerConfig) GetReadHeaderTimeout() time.Duration {
+	if h.ReadHeaderTimeout <= 0 {
+		return 5 * time.Second
+	}
+
+	return h.ReadHeaderTimeout
+}
@@ -0,0 +1,38 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/journal"
+)
+
+type JournalConfig struct {
+	Path      string        `yaml:"path"`
+	Retention time.Duration `yaml:"retention"`
+	// AdminToken — токен для /journal; пустой токен отключает эндпоинт
+	AdminToken Secret `yaml:"admin_token"`
+}
+
+func (j *JournalConfig) GetEnabled() bool {
+	return j.Path != ""
+}
+
+func (j *JournalConfig) GetPath() string {
+	return j.Path
+}
+
+func (j *JournalConfig) GetAdminEnabled() bool {
+	return j.Path != "" && j.AdminToken != ""
+}
+
+func (j *JournalConfig) GetAdminToken() string {
+	return j.AdminToken.Value()
+}
+
+func (j *JournalConfig) GetRetention() time.Duration {
+	if j.Retention <= 0 {
+		return journal.DefaultRetention
+	}
+
+	return j.Retention
+}
@@ -1,9 +1,146 @@
 package yaml
 
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IBM/sarama"
+
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/sasl"
+)
+
+// Механизмы SASL
+const (
+	SASLMechanismPlain       = "PLAIN"
+	SASLMechanismSCRAMSHA256 = "SCRAM-SHA-256"
+	SASLMechanismSCRAMSHA512 = "SCRAM-SHA-512"
+	SASLMechanismOAuthBearer = "OAUTHBEARER"
+)
+
 type KafkaConfig struct {
-	Brokers []string `yaml:"brokers"`
+	Brokers  []string    `yaml:"brokers"`
+	Version  string      `yaml:"version"`
+	ClientID string      `yaml:"client_id"`
+	TLS      *TLSConfig  `yaml:"tls"`
+	SASL     *SASLConfig `yaml:"sasl"`
+}
+
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name"`
+}
+
+type SASLConfig struct {
+	Mechanism string `yaml:"mechanism"`
+	Username  string `yaml:"username"`
+	Password  Secret `yaml:"password"`
+	Token     Secret `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
 }
 
 func (k *KafkaConfig) GetBrokers() []string {
 	return k.Brokers
 }
+
+// Apply дополняет конфигурацию клиента sarama версией протокола, client id,
+// параметрами TLS и SASL. Используется и для consumer, и для producer.
+func (k *KafkaConfig) Apply(config *sarama.Config) error {
+	if k.Version != "" {
+		version, err := sarama.ParseKafkaVersion(k.Version)
+		if err != nil {
+			return fmt.Errorf("invalid kafka version: %w", err)
+		}
+		config.Version = version
+	}
+	if k.ClientID != "" {
+		config.ClientID = k.ClientID
+	}
+
+	if k.TLS != nil && k.TLS.Enabled {
+		tlsConfig, err := k.TLS.Config()
+		if err != nil {
+			return err
+		}
+
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+
+	if k.SASL != nil && k.SASL.Mechanism != "" {
+		if err := k.SASL.apply(config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Config собирает *tls.Config из файлов сертификатов
+func (t *TLSConfig) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+		ServerName:         t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		ca, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("kafka CA file %s contains no PEM certificates", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func (s *SASLConfig) apply(config *sarama.Config) error {
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Handshake = true
+
+	switch strings.ToUpper(s.Mechanism) {
+	case SASLMechanismPlain:
+		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case SASLMechanismSCRAMSHA256:
+		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		config.Net.SASL.SCRAMClientGeneratorFunc = sasl.NewSCRAMClientGenerator(sasl.SHA256)
+	case SASLMechanismSCRAMSHA512:
+		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		config.Net.SASL.SCRAMClientGeneratorFunc = sasl.NewSCRAMClientGenerator(sasl.SHA512)
+	case SASLMechanismOAuthBearer:
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		if s.TokenFile != "" {
+			config.Net.SASL.TokenProvider = sasl.NewFileTokenProvider(s.TokenFile)
+		} else {
+			config.Net.SASL.TokenProvider = sasl.NewStaticTokenProvider(s.Token.Value())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown kafka SASL mechanism %q", s.Mechanism)
+	}
+
+	config.Net.SASL.User = s.Username
+	config.Net.SASL.Password = s.Password.Value()
+
+	return nil
+}
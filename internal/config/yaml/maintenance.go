@@ -0,0 +1,40 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/maintenance"
+)
+
+type MaintenanceConfig struct {
+	// AdminToken — токен для /maintenance; пустой токен отключает управление окнами через HTTP
+	AdminToken Secret                    `yaml:"admin_token"`
+	Windows    []MaintenanceWindowConfig `yaml:"windows"`
+}
+
+type MaintenanceWindowConfig struct {
+	App    string    `yaml:"app"`
+	Until  time.Time `yaml:"until"`
+	Reason string    `yaml:"reason"`
+}
+
+func (m *MaintenanceConfig) GetAdminEnabled() bool {
+	return m.AdminToken != ""
+}
+
+func (m *MaintenanceConfig) GetAdminToken() string {
+	return m.AdminToken.Value()
+}
+
+func (m *MaintenanceConfig) GetWindows() []maintenance.Window {
+	windows := make([]maintenance.Window, 0, len(m.Windows))
+	for _, w := range m.Windows {
+		windows = append(windows, maintenance.Window{
+			App:    w.App,
+			Until:  w.Until,
+			Reason: w.Reason,
+		})
+	}
+
+	return windows
+}
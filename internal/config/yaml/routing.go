@@ -0,0 +1,145 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
+	"github.com/major1ink/simple-notification-telegram/internal/schedule"
+)
+
+type RoutingConfig struct {
+	Default *RouteConfig `yaml:"default"`
+	Rules   []RuleConfig `yaml:"rules"`
+}
+
+type RouteConfig struct {
+	Action      string            `yaml:"action"`
+	ChatIDs     []int64           `yaml:"chat_ids"`
+	ThreadID    int               `yaml:"thread_id"`
+	MinSeverity string            `yaml:"min_severity"`
+	QuietHours  *QuietHoursConfig `yaml:"quiet_hours"`
+}
+
+// QuietHoursConfig — тихие часы маршрута
+type QuietHoursConfig struct {
+	Timezone string              `yaml:"timezone"`
+	Periods  []QuietPeriodConfig `yaml:"periods"`
+	Below    string              `yaml:"below"`
+	Action   string              `yaml:"action"`
+}
+
+type QuietPeriodConfig struct {
+	Days []string `yaml:"days"`
+	From string   `yaml:"from"`
+	To   string   `yaml:"to"`
+}
+
+type RuleConfig struct {
+	Name        string            `yaml:"name"`
+	Match       []ConditionConfig `yaml:"match"`
+	RouteConfig `yaml:",inline"`
+}
+
+type ConditionConfig struct {
+	Field string `yaml:"field"`
+	Type  string `yaml:"type"`
+	Value string `yaml:"value"`
+}
+
+func (r *RoutingConfig) GetRules() []router.Rule {
+	rules := make([]router.Rule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		conditions := make([]router.Condition, 0, len(rule.Match))
+		for _, cond := range rule.Match {
+			conditions = append(conditions, router.Condition{
+				Field: cond.Field,
+				Type:  router.MatchType(cond.Type),
+				Value: cond.Value,
+			})
+		}
+
+		rules = append(rules, router.Rule{
+			Name:       rule.Name,
+			Conditions: conditions,
+			Route:      rule.RouteConfig.route(),
+		})
+	}
+
+	return rules
+}
+
+func (r *RoutingConfig) GetDefaultRoute() router.Route {
+	if r.Default == nil {
+		return router.Route{Action: router.ActionDrop}
+	}
+
+	return r.Default.route()
+}
+
+// GetHoldUsed сообщает, что хотя бы один маршрут откладывает уведомления на тихие часы
+func (r *RoutingConfig) GetHoldUsed() bool {
+	if r.Default.holds() {
+		return true
+	}
+	for i := range r.Rules {
+		if r.Rules[i].RouteConfig.holds() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RouteConfig) holds() bool {
+	return r != nil && r.QuietHours != nil && router.QuietAction(r.QuietHours.Action) == router.QuietHold
+}
+
+func (r RouteConfig) route() router.Route {
+	minSeverity, _ := model.ParseSeverity(r.MinSeverity)
+
+	return router.Route{
+		Action:      router.Action(r.Action),
+		ChatIDs:     r.ChatIDs,
+		ThreadID:    r.ThreadID,
+		MinSeverity: minSeverity,
+		QuietHours:  r.QuietHours.quietHours(),
+	}
+}
+
+// quietHours собирает тихие часы маршрута. Ошибки значений выявляются при проверке конфигурации
+func (q *QuietHoursConfig) quietHours() *router.QuietHours {
+	if q == nil || len(q.Periods) == 0 {
+		return nil
+	}
+
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	periods := make([]schedule.Period, 0, len(q.Periods))
+	for _, p := range q.Periods {
+		period := schedule.Period{}
+		for _, name := range p.Days {
+			if day, err := schedule.ParseWeekday(name); err == nil {
+				period.Days = append(period.Days, day)
+			}
+		}
+		period.From, _ = schedule.ParseClock(p.From)
+		period.To, _ = schedule.ParseClock(p.To)
+		periods = append(periods, period)
+	}
+
+	below, _ := model.ParseSeverity(q.Below)
+	action := router.QuietAction(q.Action)
+	if action == "" {
+		action = router.QuietSilent
+	}
+
+	return &router.QuietHours{
+		Schedule: schedule.Schedule{Location: location, Periods: periods},
+		Below:    below,
+		Action:   action,
+	}
+}
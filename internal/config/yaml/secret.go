@@ -0,0 +1,33 @@
+package yaml
+
+// redacted заменяет значение секрета при выводе конфигурации
+const redacted = "***"
+
+// Secret — строковое значение, которое не должно попадать в логи.
+// При форматировании и сериализации выводится как "***".
+type Secret string
+
+// Value возвращает значение секрета
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
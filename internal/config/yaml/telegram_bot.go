@@ -1,14 +1,97 @@
 package yaml
 
+import (
+	"strings"
+
+	"github.com/major1ink/simple-notification-telegram/internal/client/http/telegram"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	telegramService "github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+)
+
 type TelegramConfig struct {
-	TelegramBotToken string `yaml:"telegram_bot_token"`
-	TelegramChatID   int64  `yaml:"telegram_chat_id"`
+	TelegramBotToken     Secret           `yaml:"telegram_bot_token"`
+	TelegramBotTokenFile string           `yaml:"telegram_bot_token_file"`
+	TelegramChatID       int64            `yaml:"telegram_chat_id"`
+	RateLimit            *RateLimitConfig `yaml:"rate_limit"`
+	LongMessageMode      string           `yaml:"long_message_mode"`
+	ParseMode            string           `yaml:"parse_mode"`
+	SilentBelow          string           `yaml:"silent_below"`
+}
+
+type RateLimitConfig struct {
+	GlobalPerSecond       float64 `yaml:"global_per_second"`
+	GlobalBurst           int     `yaml:"global_burst"`
+	PerChatPerMinute      float64 `yaml:"per_chat_per_minute"`
+	PrivatePerSecond      float64 `yaml:"private_per_second"`
+	PerChatBurst          int     `yaml:"per_chat_burst"`
+	MaxRetryAfterAttempts *int    `yaml:"max_retry_after_attempts"`
 }
 
 func (t *TelegramConfig) GetTelegramBotToken() string {
-	return t.TelegramBotToken
+	return t.TelegramBotToken.Value()
 }
 
 func (t *TelegramConfig) GetTelegramChatID() int64 {
 	return t.TelegramChatID
 }
+
+func (t *TelegramConfig) GetMessageConfig() telegramService.Config {
+	mode := telegramService.LongMessageMode(t.LongMessageMode)
+	if mode == "" {
+		mode = telegramService.LongMessageSplit
+	}
+
+	return telegramService.Config{
+		LongMessageMode: mode,
+		ParseMode:       t.GetParseMode(),
+		SilentBelow:     t.GetSilentBelow(),
+	}
+}
+
+// GetParseMode возвращает режим разметки. По умолчанию Markdown, как до появления настройки
+func (t *TelegramConfig) GetParseMode() model.ParseMode {
+	switch strings.ToLower(t.ParseMode) {
+	case "plain", "none", "text":
+		return model.ParseModePlain
+	case "markdownv2":
+		return model.ParseModeMarkdownV2
+	case "html":
+		return model.ParseModeHTML
+	default:
+		return model.ParseModeMarkdown
+	}
+}
+
+// GetSilentBelow возвращает уровень важности, ниже которого сообщения отправляются без звука
+func (t *TelegramConfig) GetSilentBelow() model.Severity {
+	severity, _ := model.ParseSeverity(t.SilentBelow)
+	return severity
+}
+
+func (t *TelegramConfig) GetRateLimit() telegram.RateLimitConfig {
+	rateLimit := telegram.DefaultRateLimitConfig()
+	if t.RateLimit == nil {
+		return rateLimit
+	}
+
+	if t.RateLimit.GlobalPerSecond > 0 {
+		rateLimit.GlobalPerSecond = t.RateLimit.GlobalPerSecond
+	}
+	if t.RateLimit.GlobalBurst > 0 {
+		rateLimit.GlobalBurst = t.RateLimit.GlobalBurst
+	}
+	if t.RateLimit.PerChatPerMinute > 0 {
+		rateLimit.PerChatPerMinute = t.RateLimit.PerChatPerMinute
+	}
+	if t.RateLimit.PrivatePerSecond > 0 {
+		rateLimit.PrivatePerSecond = t.RateLimit.PrivatePerSecond
+	}
+	if t.RateLimit.PerChatBurst > 0 {
+		rateLimit.PerChatBurst = t.RateLimit.PerChatBurst
+	}
+	if t.RateLimit.MaxRetryAfterAttempts != nil {
+		rateLimit.MaxRetryAfterAttempts = *t.RateLimit.MaxRetryAfterAttempts
+	}
+
+	return rateLimit
+}
@@ -0,0 +1,49 @@
+package yaml
+
+import (
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/service/telegram"
+)
+
+type TemplatesConfig struct {
+	Dir     string               `yaml:"dir"`
+	Default string               `yaml:"default"`
+	Status  string               `yaml:"status"`
+	Rules   []TemplateRuleConfig `yaml:"rules"`
+	// SeverityPrefixes — префиксы уровней важности для функции severityPrefix: уровень → текст
+	SeverityPrefixes map[string]string `yaml:"severity_prefixes"`
+}
+
+type TemplateRuleConfig struct {
+	Binding   string `yaml:"binding"`
+	App       string `yaml:"app"`
+	TypeEvent string `yaml:"type_event"`
+	Template  string `yaml:"template"`
+}
+
+func (t *TemplatesConfig) GetTemplates() telegram.TemplateConfig {
+	rules := make([]telegram.TemplateRule, 0, len(t.Rules))
+	for _, rule := range t.Rules {
+		rules = append(rules, telegram.TemplateRule{
+			Binding:   rule.Binding,
+			App:       rule.App,
+			TypeEvent: rule.TypeEvent,
+			Template:  rule.Template,
+		})
+	}
+
+	prefixes := make(map[model.Severity]string, len(t.SeverityPrefixes))
+	for name, prefix := range t.SeverityPrefixes {
+		if severity, ok := model.ParseSeverity(name); ok {
+			prefixes[severity] = prefix
+		}
+	}
+
+	return telegram.TemplateConfig{
+		Dir:              t.Dir,
+		Default:          t.Default,
+		Status:           t.Status,
+		Rules:            rules,
+		SeverityPrefixes: prefixes,
+	}
+}
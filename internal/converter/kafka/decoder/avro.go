@@ -0,0 +1,156 @@
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// NameAvro — имя декодера Avro в формате Confluent (magic byte + ID схемы)
+const NameAvro = "avro"
+
+// Заголовок Confluent wire format: нулевой magic byte и 4 байта ID схемы (big-endian)
+const (
+	confluentMagicByte  = 0
+	confluentHeaderSize = 5
+)
+
+var (
+	// ErrUnknownSchema — схема с ID из сообщения не найдена в реестре или директории
+	ErrUnknownSchema = errors.New("unknown avro schema")
+	// ErrCorruptPayload — сообщение не соответствует формату или схеме
+	ErrCorruptPayload = errors.New("corrupt avro payload")
+	// ErrSchemaUnavailable — реестр схем временно недоступен, сообщение стоит обработать повторно
+	ErrSchemaUnavailable = errors.New("schema registry unavailable")
+)
+
+// SchemaSource возвращает текст схемы Avro по ID.
+// Если схема не найдена, ошибка должна оборачивать ErrUnknownSchema.
+type SchemaSource interface {
+	Schema(ctx context.Context, id int) (string, error)
+}
+
+// AvroConfig — параметры декодирования Avro
+type AvroConfig struct {
+	Registry  RegistryConfig // Реестр схем (если задан URL)
+	SchemaDir string         // Директория с файлами <id>.avsc для работы без реестра
+	Fields    FieldMapping
+}
+
+type decoderAvro struct {
+	source SchemaSource
+	fields fieldPaths
+
+	mu     sync.RWMutex
+	codecs map[int]*goavro.Codec
+}
+
+// NewAvroDecoder создаёт декодер Avro. Схемы берутся из реестра, если задан его URL,
+// иначе — из директории с файлами .avsc
+func NewAvroDecoder(cfg AvroConfig) (*decoderAvro, error) {
+	var source SchemaSource
+	switch {
+	case cfg.Registry.URL != "":
+		source = NewRegistrySchemaSource(cfg.Registry)
+	case cfg.SchemaDir != "":
+		dirSource, err := NewDirSchemaSource(cfg.SchemaDir)
+		if err != nil {
+			return nil, err
+		}
+		source = dirSource
+	default:
+		return nil, errors.New("avro decoder requires a schema registry URL or a schema directory")
+	}
+
+	return NewAvroDecoderWithSource(source, cfg.Fields), nil
+}
+
+// NewAvroDecoderWithSource создаёт декодер Avro с произвольным источником схем
+func NewAvroDecoderWithSource(source SchemaSource, fields FieldMapping) *decoderAvro {
+	return &decoderAvro{
+		source: source,
+		fields: fields.withDefaults().paths(),
+		codecs: make(map[int]*goavro.Codec),
+	}
+}
+
+func (d *decoderAvro) Decode(ctx context.Context, msg consumer.Message) (model.AssembledEvent, error) {
+	if len(msg.Value) < confluentHeaderSize || msg.Value[0] != confluentMagicByte {
+		return model.AssembledEvent{}, fmt.Errorf("%w: missing confluent wire format header", ErrCorruptPayload)
+	}
+	id := int(binary.BigEndian.Uint32(msg.Value[1:confluentHeaderSize]))
+
+	codec, err := d.codec(ctx, id)
+	if err != nil {
+		return model.AssembledEvent{}, err
+	}
+
+	native, rest, err := codec.NativeFromBinary(msg.Value[confluentHeaderSize:])
+	if err != nil {
+		return model.AssembledEvent{}, fmt.Errorf("%w: schema %d: %v", ErrCorruptPayload, id, err)
+	}
+	if len(rest) > 0 {
+		return model.AssembledEvent{}, fmt.Errorf("%w: schema %d: %d trailing bytes", ErrCorruptPayload, id, len(rest))
+	}
+
+	fields, err := avroFields(codec, native)
+	if err != nil {
+		return model.AssembledEvent{}, fmt.Errorf("%w: schema %d: %v", ErrCorruptPayload, id, err)
+	}
+
+	return mapEvent(fields, d.fields), nil
+}
+
+// codec возвращает кодек схемы, загружая её при первом обращении
+func (d *decoderAvro) codec(ctx context.Context, id int) (*goavro.Codec, error) {
+	d.mu.RLock()
+	codec, ok := d.codecs[id]
+	d.mu.RUnlock()
+	if ok {
+		return codec, nil
+	}
+
+	schema, err := d.source.Schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Стандартный JSON без обёрток union ({"string": "..."}) удобнее для шаблонов
+	codec, err = goavro.NewCodecForStandardJSONFull(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %d: %w", id, err)
+	}
+
+	d.mu.Lock()
+	d.codecs[id] = codec
+	d.mu.Unlock()
+
+	return codec, nil
+}
+
+// avroFields переводит запись Avro в map через JSON-представление
+func avroFields(codec *goavro.Codec, native any) (map[string]any, error) {
+	data, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	fields := make(map[string]any)
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("avro schema is not a record: %w", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,119 @@
+package decoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRegistryTimeout — таймаут запроса к реестру схем по умолчанию
+const defaultRegistryTimeout = 10 * time.Second
+
+// RegistryConfig — параметры подключения к Confluent Schema Registry
+type RegistryConfig struct {
+	URL      string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+type registrySchemaSource struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewRegistrySchemaSource создаёт источник схем, запрашивающий их из реестра по ID
+func NewRegistrySchemaSource(cfg RegistryConfig) *registrySchemaSource {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultRegistryTimeout
+	}
+
+	return &registrySchemaSource{
+		url:      strings.TrimSuffix(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *registrySchemaSource) Schema(ctx context.Context, id int) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/schemas/ids/"+strconv.Itoa(id), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSchemaUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: id %d not found in schema registry", ErrUnknownSchema, id)
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return "", fmt.Errorf("%w: status %d", ErrSchemaUnavailable, resp.StatusCode)
+	// Ошибка учётных данных не зависит от сообщения: повтор после её исправления доставит его
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("%w: status %d, check registry credentials", ErrSchemaUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("schema registry returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var body struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse schema registry response: %w", err)
+	}
+	if body.Schema == "" {
+		return "", fmt.Errorf("%w: id %d has empty schema", ErrUnknownSchema, id)
+	}
+
+	return body.Schema, nil
+}
+
+type dirSchemaSource struct {
+	dir string
+}
+
+// NewDirSchemaSource создаёт источник схем из директории с файлами <id>.avsc
+func NewDirSchemaSource(dir string) (*dirSchemaSource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("schema directory %s is not a directory", dir)
+	}
+
+	return &dirSchemaSource{dir: dir}, nil
+}
+
+func (s *dirSchemaSource) Schema(_ context.Context, id int) (string, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, strconv.Itoa(id)+".avsc"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: id %d not found in %s", ErrUnknownSchema, id, s.dir)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,191 @@
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// NameCloudEvents — имя декодера CloudEvents (Kafka protocol binding, binary и structured режимы)
+const NameCloudEvents = "cloudevents"
+
+const (
+	// cloudEventsHeaderPrefix — префикс заголовков с атрибутами события в binary режиме
+	cloudEventsHeaderPrefix = "ce_"
+	// cloudEventsContentType — тип содержимого сообщения в structured режиме
+	cloudEventsContentType = "application/cloudevents+json"
+	contentTypeHeader      = "content-type"
+)
+
+// CloudEventsConfig — параметры декодирования CloudEvents
+type CloudEventsConfig struct {
+	Fields FieldMapping
+}
+
+// DefaultCloudEventsFieldMapping — атрибуты CloudEvents для полей события
+func DefaultCloudEventsFieldMapping() FieldMapping {
+	return FieldMapping{
+		EventUuid: "id",
+		TypeEvent: "type",
+		App:       "source",
+		Message:   "data",
+		Severity:  "severity",
+	}
+}
+
+type decoderCloudEvents struct {
+	fields fieldPaths
+}
+
+func NewCloudEventsDecoder(cfg CloudEventsConfig) *decoderCloudEvents {
+	fields := cfg.Fields
+	def := DefaultCloudEventsFieldMapping()
+	if fields.EventUuid == "" {
+		fields.EventUuid = def.EventUuid
+	}
+	if fields.TypeEvent == "" {
+		fields.TypeEvent = def.TypeEvent
+	}
+	if fields.App == "" {
+		fields.App = def.App
+	}
+	if fields.Message == "" {
+		fields.Message = def.Message
+	}
+	if fields.Severity == "" {
+		fields.Severity = def.Severity
+	}
+
+	return &decoderCloudEvents{fields: fields.paths()}
+}
+
+// Decode разбирает событие. Режим определяется по заголовкам: при наличии
+// ce_specversion — binary, иначе сообщение читается как structured JSON.
+// Атрибуты (включая расширения) и data доступны шаблонам как .Fields
+func (d *decoderCloudEvents) Decode(_ context.Context, msg consumer.Message) (model.AssembledEvent, error) {
+	var (
+		attributes map[string]any
+		err        error
+	)
+	if _, ok := msg.Headers[cloudEventsHeaderPrefix+"specversion"]; ok {
+		attributes, err = binaryCloudEvent(msg)
+	} else {
+		attributes, err = structuredCloudEvent(msg)
+	}
+	if err != nil {
+		return model.AssembledEvent{}, err
+	}
+
+	for _, name := range []string{"specversion", "id", "source", "type"} {
+		if valueString(attributes[name]) == "" {
+			return model.AssembledEvent{}, fmt.Errorf("cloudevent: required attribute %q is missing", name)
+		}
+	}
+
+	event := mapEvent(attributes, d.fields)
+	if value := valueString(attributes["time"]); value != "" {
+		if event.Time, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return model.AssembledEvent{}, fmt.Errorf("cloudevent: invalid time %q: %w", value, err)
+		}
+	}
+
+	return event, nil
+}
+
+// binaryCloudEvent читает атрибуты из заголовков ce_*, а data — из тела сообщения
+func binaryCloudEvent(msg consumer.Message) (map[string]any, error) {
+	attributes := make(map[string]any, len(msg.Headers))
+	for key, value := range msg.Headers {
+		if name, ok := strings.CutPrefix(key, cloudEventsHeaderPrefix); ok && name != "" {
+			attributes[name] = string(value)
+		}
+	}
+
+	contentType := string(msg.Headers[contentTypeHeader])
+	if contentType != "" {
+		attributes["datacontenttype"] = contentType
+	}
+
+	if len(msg.Value) == 0 {
+		return attributes, nil
+	}
+
+	attributes["data"] = string(msg.Value)
+	if isJSONContentType(contentType) {
+		data, err := decodeJSON(msg.Value)
+		switch {
+		case err == nil:
+			attributes["data"] = data
+		case contentType != "":
+			return nil, fmt.Errorf("cloudevent: failed to unmarshal data: %w", err)
+		}
+	}
+
+	return attributes, nil
+}
+
+// structuredCloudEvent разбирает событие в формате application/cloudevents+json
+func structuredCloudEvent(msg consumer.Message) (map[string]any, error) {
+	if contentType := string(msg.Headers[contentTypeHeader]); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != cloudEventsContentType {
+			return nil, fmt.Errorf("cloudevent: unsupported content type %q, expected %s or ce_* headers",
+				contentType, cloudEventsContentType)
+		}
+	}
+
+	value, err := decodeJSON(msg.Value)
+	if err != nil {
+		return nil, fmt.Errorf("cloudevent: failed to unmarshal json: %w", err)
+	}
+	attributes, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cloudevent: structured event must be a json object")
+	}
+
+	if encoded, ok := attributes["data_base64"].(string); ok {
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cloudevent: invalid data_base64: %w", err)
+		}
+		delete(attributes, "data_base64")
+		attributes["data"] = string(data)
+	}
+
+	return attributes, nil
+}
+
+// isJSONContentType сообщает, содержит ли data JSON. Без типа содержимого
+// data разбирается как JSON, а если это не удалось — остаётся строкой
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,237 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// FieldMapping — пути к полям исходного сообщения, из которых заполняется событие.
+// Вложенные поля задаются через точку: "meta.service" или "$.meta.service" (см. ParsePath).
+type FieldMapping struct {
+	EventUuid string
+	TypeEvent string
+	App       string
+	Message   string
+	Severity  string
+}
+
+// DefaultFieldMapping — поля с именами как у AssembledEvent
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		EventUuid: "event_uuid",
+		TypeEvent: "type_event",
+		App:       "app",
+		Message:   "message",
+		Severity:  "severity",
+	}
+}
+
+// withDefaults заполняет незаданные пути значениями по умолчанию
+func (m FieldMapping) withDefaults() FieldMapping {
+	def := DefaultFieldMapping()
+	if m.EventUuid == "" {
+		m.EventUuid = def.EventUuid
+	}
+	if m.TypeEvent == "" {
+		m.TypeEvent = def.TypeEvent
+	}
+	if m.App == "" {
+		m.App = def.App
+	}
+	if m.Message == "" {
+		m.Message = def.Message
+	}
+	if m.Severity == "" {
+		m.Severity = def.Severity
+	}
+
+	return m
+}
+
+// fieldPaths — пути FieldMapping, разобранные один раз при создании декодера
+type fieldPaths struct {
+	eventUuid Path
+	typeEvent Path
+	app       Path
+	message   Path
+	severity  Path
+}
+
+// paths разбирает пути к полям. Синтаксис путей проверяется при загрузке конфигурации,
+// неверный путь не находит значения
+func (m FieldMapping) paths() fieldPaths {
+	return fieldPaths{
+		eventUuid: pathOf(m.EventUuid),
+		typeEvent: pathOf(m.TypeEvent),
+		app:       pathOf(m.App),
+		message:   pathOf(m.Message),
+		severity:  pathOf(m.Severity),
+	}
+}
+
+// event заполняет поля события из документа
+func (p fieldPaths) event(document any) model.AssembledEvent {
+	severity, _ := model.ParseSeverity(p.severity.LookupString(document))
+
+	return model.AssembledEvent{
+		EventUuid: p.eventUuid.LookupString(document),
+		TypeEvent: p.typeEvent.LookupString(document),
+		App:       p.app.LookupString(document),
+		Message:   p.message.LookupString(document),
+		Severity:  severity,
+	}
+}
+
+// mapEvent собирает событие из полей сообщения. Все поля доступны шаблонам как .Fields и .Raw
+func mapEvent(fields map[string]any, paths fieldPaths) model.AssembledEvent {
+	event := paths.event(fields)
+	event.Fields = fields
+	event.Raw = fields
+
+	return event
+}
+
+// pathSegment — ключ объекта или индекс массива в пути к полю
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Path — разобранный путь к полю документа
+type Path struct {
+	segments []pathSegment
+	valid    bool
+}
+
+// ParsePath разбирает путь к полю документа. Поддерживается JSONPath-подобный синтаксис:
+// "$.meta.uuid", "items[0].name", "meta['key.with.dots']" и короткая запись "meta.uuid", "items.0.name"
+func ParsePath(path string) (Path, error) {
+	segments, err := parseSegments(path)
+	if err != nil {
+		return Path{}, err
+	}
+
+	return Path{segments: segments, valid: true}, nil
+}
+
+// pathOf разбирает путь, проверенный при загрузке конфигурации. Неверный путь не находит значения
+func pathOf(path string) Path {
+	p, _ := ParsePath(path)
+	return p
+}
+
+func parseSegments(path string) ([]pathSegment, error) {
+	rest := strings.TrimPrefix(path, "$")
+	if rest == "" {
+		if path == "$" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("empty path")
+	}
+	if rest != path && rest[0] != '.' && rest[0] != '[' {
+		return nil, fmt.Errorf("invalid path %q: expected '.' or '[' after '$'", path)
+	}
+	if rest != path && rest[0] == '.' {
+		if rest = rest[1:]; rest == "" {
+			return nil, fmt.Errorf("invalid path %q: empty key", path)
+		}
+	}
+
+	var segments []pathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '['", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1]})
+				break
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index [%s]", path, inner)
+			}
+			segments = append(segments, pathSegment{key: inner, index: index, isIndex: true})
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+
+			segment := pathSegment{key: key}
+			if index, err := strconv.Atoi(key); err == nil && index >= 0 {
+				segment.index, segment.isIndex = index, true
+			}
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments, nil
+}
+
+// Lookup возвращает значение по пути
+func (p Path) Lookup(document any) (any, bool) {
+	if !p.valid {
+		return nil, false
+	}
+
+	value := document
+	for _, segment := range p.segments {
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = v[segment.key]; !ok {
+				return nil, false
+			}
+		case []any:
+			if !segment.isIndex || segment.index >= len(v) {
+				return nil, false
+			}
+			value = v[segment.index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// LookupString возвращает значение по пути строкой. Объекты и массивы выводятся как JSON
+func (p Path) LookupString(document any) string {
+	value, _ := p.Lookup(document)
+	return valueString(value)
+}
+
+// valueString выводит значение документа строкой. Объекты и массивы выводятся как JSON
+func valueString(value any) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	}
+
+	return fmt.Sprint(value)
+}
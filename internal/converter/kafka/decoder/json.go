@@ -0,0 +1,58 @@
+package decoder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// NameJSON — имя декодера JSON произвольной структуры с настраиваемыми путями к полям
+const NameJSON = "json"
+
+// JSONConfig — параметры декодирования JSON
+type JSONConfig struct {
+	Fields FieldMapping
+	// Extra — дополнительные значения для шаблонов: имя → путь в документе
+	Extra map[string]string
+}
+
+type decoderJSON struct {
+	fields fieldPaths
+	extra  map[string]Path
+}
+
+func NewJSONDecoder(cfg JSONConfig) *decoderJSON {
+	extra := make(map[string]Path, len(cfg.Extra))
+	for name, path := range cfg.Extra {
+		extra[name] = pathOf(path)
+	}
+
+	return &decoderJSON{
+		fields: cfg.Fields.withDefaults().paths(),
+		extra:  extra,
+	}
+}
+
+// Decode разбирает документ и заполняет поля события по путям из конфигурации.
+// Документ целиком доступен шаблонам как .Raw, значения из extra — как .Fields
+func (d *decoderJSON) Decode(_ context.Context, msg consumer.Message) (model.AssembledEvent, error) {
+	document, err := decodeJSON(msg.Value)
+	if err != nil {
+		return model.AssembledEvent{}, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	fields := make(map[string]any, len(d.extra))
+	for name, path := range d.extra {
+		if value, ok := path.Lookup(document); ok {
+			fields[name] = value
+		}
+	}
+
+	event := d.fields.event(document)
+	event.Fields = fields
+	event.Raw = document
+
+	return event, nil
+}
@@ -1,23 +1,29 @@
 package decoder
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
 )
 
+// NameAssembled — имя декодера событий в формате AssembledEvent (JSON)
+const NameAssembled = "assembled"
+
 type decoderAssembled struct{}
 
 func NewOrderDecoderAssembled() *decoderAssembled {
 	return &decoderAssembled{}
 }
 
-func (d *decoderAssembled) DecodeAssembled(data []byte) (model.AssembledEvent, error) {
+func (d *decoderAssembled) Decode(_ context.Context, msg consumer.Message) (model.AssembledEvent, error) {
 	var event model.AssembledEvent
-	if err := json.Unmarshal(data, &event); err != nil {
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
 		return model.AssembledEvent{}, fmt.Errorf("failed to unmarshal json: %w", err)
 	}
+	event.Raw, _ = decodeJSON(msg.Value)
 
 	return event, nil
 }
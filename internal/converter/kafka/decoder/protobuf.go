@@ -0,0 +1,137 @@
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// NameProtobuf — имя декодера protobuf-сообщений
+const NameProtobuf = "protobuf"
+
+// typeURLPrefix — префикс имени типа в формате google.protobuf.Any
+const typeURLPrefix = "type.googleapis.com/"
+
+// ProtobufConfig — параметры декодирования protobuf
+type ProtobufConfig struct {
+	DescriptorSet string // Файл FileDescriptorSet (protoc --include_imports --descriptor_set_out)
+	MessageType   string // Полное имя типа сообщения
+	TypeHeader    string // Заголовок kafka с полным именем типа (приоритетнее MessageType)
+	Fields        FieldMapping
+}
+
+type decoderProtobuf struct {
+	files       *protoregistry.Files
+	messageType string
+	typeHeader  string
+	fields      fieldPaths
+}
+
+// NewProtobufDecoder загружает описания типов из файла descriptor set
+func NewProtobufDecoder(cfg ProtobufConfig) (*decoderProtobuf, error) {
+	content, err := os.ReadFile(cfg.DescriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set %s: %w", cfg.DescriptorSet, err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load descriptor set %s: %w", cfg.DescriptorSet, err)
+	}
+
+	d := &decoderProtobuf{
+		files:       files,
+		messageType: cfg.MessageType,
+		typeHeader:  cfg.TypeHeader,
+		fields:      cfg.Fields.withDefaults().paths(),
+	}
+
+	if cfg.MessageType != "" {
+		if _, err := d.descriptor(cfg.MessageType); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+func (d *decoderProtobuf) Decode(_ context.Context, msg consumer.Message) (model.AssembledEvent, error) {
+	messageType := d.messageType
+	if d.typeHeader != "" {
+		if value, ok := msg.Headers[d.typeHeader]; ok && len(value) > 0 {
+			messageType = strings.TrimPrefix(string(value), typeURLPrefix)
+		}
+	}
+	if messageType == "" {
+		return model.AssembledEvent{}, fmt.Errorf("protobuf message type is not set and header %q is missing", d.typeHeader)
+	}
+
+	descriptor, err := d.descriptor(messageType)
+	if err != nil {
+		return model.AssembledEvent{}, err
+	}
+
+	message := dynamicpb.NewMessage(descriptor)
+	if err := proto.Unmarshal(msg.Value, message); err != nil {
+		return model.AssembledEvent{}, fmt.Errorf("failed to unmarshal protobuf %s: %w", messageType, err)
+	}
+
+	fields, err := protoFields(message)
+	if err != nil {
+		return model.AssembledEvent{}, err
+	}
+
+	return mapEvent(fields, d.fields), nil
+}
+
+func (d *decoderProtobuf) descriptor(name string) (protoreflect.MessageDescriptor, error) {
+	desc, err := d.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf message type %s not found in descriptor set: %w", name, err)
+	}
+
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a protobuf message type", name)
+	}
+
+	return message, nil
+}
+
+// protoFields переводит сообщение в map через каноническое JSON-представление
+// с исходными именами полей
+func protoFields(message proto.Message) (map[string]any, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert protobuf message: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	fields := make(map[string]any)
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to convert protobuf message: %w", err)
+	}
+
+	return fields, nil
+}
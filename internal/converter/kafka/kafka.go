@@ -1,7 +1,15 @@
 package kafka
 
-import "github.com/major1ink/simple-notification-telegram/internal/model"
+import (
+	"context"
 
-type OrderAssembledDecoder interface {
-	DecodeAssembled(data []byte) (model.AssembledEvent, error)
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+// Decoder преобразует сообщение kafka в событие уведомления
+type Decoder interface {
+	// Decode получает контекст обработки сообщения: декодер, обращающийся к внешним
+	// сервисам, прерывает запрос при ребалансировке и остановке
+	Decode(ctx context.Context, msg consumer.Message) (model.AssembledEvent, error)
 }
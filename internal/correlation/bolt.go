@@ -0,0 +1,139 @@
+package correlation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// maxCleanupInterval — наибольший интервал удаления истёкших ключей
+const maxCleanupInterval = time.Hour
+
+var boltBucket = []byte("incidents")
+
+type boltEntry struct {
+	Messages []model.SentMessage `json:"messages"`
+	Expires  time.Time           `json:"expires"`
+}
+
+// boltStore хранит связи в файле bbolt, чтобы обновление сообщений работало после перезапуска
+type boltStore struct {
+	db   *bolt.DB
+	ttl  time.Duration
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewBoltStore(path string, ttl time.Duration) (*boltStore, error) {
+	// Таймаут не даёт зависнуть, если файл занят другим процессом
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open correlation store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init correlation store %s: %w", path, err)
+	}
+
+	s := &boltStore{
+		db:   db,
+		ttl:  ttl,
+		done: make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.cleanup(min(ttl, maxCleanupInterval))
+
+	return s, nil
+}
+
+func (s *boltStore) Get(key string) ([]model.SentMessage, error) {
+	var entry boltEntry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltBucket).Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+		return json.Unmarshal(value, &entry)
+	})
+	if err != nil || time.Now().After(entry.Expires) {
+		return nil, err
+	}
+
+	return entry.Messages, nil
+}
+
+func (s *boltStore) Put(key string, messages []model.SentMessage) error {
+	value, err := json.Marshal(boltEntry{
+		Messages: messages,
+		Expires:  time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), value)
+	})
+}
+
+func (s *boltStore) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	return s.db.Close()
+}
+
+func (s *boltStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			_ = s.deleteExpired()
+		}
+	}
+}
+
+func (s *boltStore) deleteExpired() error {
+	now := time.Now()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+
+		// Удаление во время обхода курсором пропускает элементы, поэтому ключи собираются заранее
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var entry boltEntry
+			if json.Unmarshal(value, &entry) != nil || now.After(entry.Expires) {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
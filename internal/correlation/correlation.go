@@ -0,0 +1,31 @@
+package correlation
+
+import (
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// DefaultTTL — срок хранения связи ключа с сообщениями по умолчанию
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Store хранит сообщения, отправленные по ключу корреляции инцидента
+type Store interface {
+	// Get возвращает сообщения по ключу; nil — ключ не встречался или истёк
+	Get(key string) ([]model.SentMessage, error)
+	// Put сохраняет сообщения по ключу на время TTL
+	Put(key string, messages []model.SentMessage) error
+	Close() error
+}
+
+// New создаёт хранилище: в файле bbolt, если указан path, иначе в памяти
+func New(path string, ttl time.Duration) (Store, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if path == "" {
+		return NewMemoryStore(ttl), nil
+	}
+
+	return NewBoltStore(path, ttl)
+}
@@ -0,0 +1,66 @@
+package correlation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+type memoryEntry struct {
+	messages []model.SentMessage
+	expires  time.Time
+}
+
+// memoryStore хранит связи в памяти. Истёкшие ключи удаляются при записи
+type memoryStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]memoryEntry
+	nextPrune time.Time
+}
+
+func NewMemoryStore(ttl time.Duration) *memoryStore {
+	return &memoryStore{
+		ttl:     ttl,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (s *memoryStore) Get(key string) ([]model.SentMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, nil
+	}
+
+	return append([]model.SentMessage(nil), entry.messages...), nil
+}
+
+func (s *memoryStore) Put(key string, messages []model.SentMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[key] = memoryEntry{
+		messages: append([]model.SentMessage(nil), messages...),
+		expires:  now.Add(s.ttl),
+	}
+
+	if now.After(s.nextPrune) {
+		for k, entry := range s.entries {
+			if now.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.nextPrune = now.Add(min(s.ttl, maxCleanupInterval))
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package dedup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxCleanupInterval — наибольший интервал удаления просроченных ключей из файла
+const maxCleanupInterval = time.Hour
+
+var boltBucket = []byte("events")
+
+// boltStore хранит ключи в файле bbolt, чтобы дедупликация переживала перезапуск.
+// Значение ключа — время истечения в наносекундах Unix.
+type boltStore struct {
+	db   *bolt.DB
+	ttl  time.Duration
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewBoltStore(path string, ttl time.Duration) (*boltStore, error) {
+	// Таймаут не даёт зависнуть, если файл занят другим процессом
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dedup store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init dedup store %s: %w", path, err)
+	}
+
+	s := &boltStore{
+		db:   db,
+		ttl:  ttl,
+		done: make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.cleanup(min(ttl, maxCleanupInterval))
+
+	return s, nil
+}
+
+func (s *boltStore) Seen(key string) (bool, error) {
+	var seen bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltBucket).Get([]byte(key))
+		seen = len(value) == 8 && time.Now().UnixNano() < int64(binary.BigEndian.Uint64(value))
+		return nil
+	})
+
+	return seen, err
+}
+
+func (s *boltStore) Add(key string) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(time.Now().Add(s.ttl).UnixNano()))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), value)
+	})
+}
+
+func (s *boltStore) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	return s.db.Close()
+}
+
+// cleanup периодически удаляет просроченные ключи
+func (s *boltStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			_ = s.deleteExpired()
+		}
+	}
+}
+
+func (s *boltStore) deleteExpired() error {
+	now := time.Now().UnixNano()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+
+		// Удаление во время обхода курсором пропускает элементы, поэтому ключи собираются заранее
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			if len(value) != 8 || int64(binary.BigEndian.Uint64(value)) <= now {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package dedup
+
+import (
+	"fmt"
+	"time"
+)
+
+// Хранилища ключей обработанных событий
+const (
+	BackendMemory = "memory"
+	BackendBolt   = "bolt"
+)
+
+// DefaultPath — файл базы bolt по умолчанию
+const DefaultPath = "dedup.db"
+
+const (
+	defaultTTL        = 24 * time.Hour
+	defaultMaxEntries = 100000
+)
+
+// Store — хранилище ключей уже доставленных событий
+type Store interface {
+	// Seen сообщает, встречался ли ключ в пределах TTL
+	Seen(key string) (bool, error)
+	// Add запоминает ключ на время TTL
+	Add(key string) error
+	Close() error
+}
+
+// Config — параметры хранилища
+type Config struct {
+	Backend    string
+	TTL        time.Duration
+	MaxEntries int    // Размер LRU для memory
+	Path       string // Файл базы для bolt
+}
+
+func (c Config) withDefaults() Config {
+	if c.TTL <= 0 {
+		c.TTL = defaultTTL
+	}
+	if c.MaxEntries <= 0 {
+		c.MaxEntries = defaultMaxEntries
+	}
+	if c.Path == "" {
+		c.Path = DefaultPath
+	}
+
+	return c
+}
+
+// New создаёт хранилище выбранного типа
+func New(cfg Config) (Store, error) {
+	cfg = cfg.withDefaults()
+
+	switch cfg.Backend {
+	case BackendMemory:
+		return NewMemoryStore(cfg.TTL, cfg.MaxEntries), nil
+	case BackendBolt:
+		return NewBoltStore(cfg.Path, cfg.TTL)
+	default:
+		return nil, fmt.Errorf("unknown dedup backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,76 @@
+package dedup
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key     string
+	expires time.Time
+}
+
+// memoryStore — LRU в памяти: при переполнении вытесняются ключи, которые дольше всех
+// не проверялись и не добавлялись. Повторяющийся ключ остаётся в начале списка
+type memoryStore struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+func NewMemoryStore(ttl time.Duration, maxEntries int) *memoryStore {
+	return &memoryStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (s *memoryStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(element.Value.(*memoryEntry).expires) {
+		s.remove(element)
+		return false, nil
+	}
+	s.order.MoveToFront(element)
+
+	return true, nil
+}
+
+func (s *memoryStore) Add(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(s.ttl)
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryEntry).expires = expires
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, expires: expires})
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+func (s *memoryStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*memoryEntry).key)
+}
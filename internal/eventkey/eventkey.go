@@ -0,0 +1,41 @@
+package eventkey
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// Поля события, из которых берётся ключ (дедупликации, корреляции).
+// Заголовки kafka задаются как "header:<имя>", поля исходного документа — путём "$.<путь>".
+const (
+	FieldEventUuid = "event_uuid"
+	FieldKafkaKey  = "key"
+	FieldHeader    = "header:"
+)
+
+// Func возвращает ключ события. Пустой ключ — у события нет ключа
+type Func func(event model.AssembledEvent) string
+
+// New создаёт функцию получения ключа по имени поля. Пустое имя — event_uuid
+func New(field string) (Func, error) {
+	switch {
+	case field == "" || field == FieldEventUuid:
+		return func(event model.AssembledEvent) string { return event.EventUuid }, nil
+	case field == FieldKafkaKey:
+		return func(event model.AssembledEvent) string { return event.Kafka.Key }, nil
+	case strings.HasPrefix(field, FieldHeader) && len(field) > len(FieldHeader):
+		name := strings.TrimPrefix(field, FieldHeader)
+		return func(event model.AssembledEvent) string { return event.Kafka.Headers[name] }, nil
+	case strings.HasPrefix(field, "$"):
+		path, err := decoder.ParsePath(field)
+		if err != nil {
+			return nil, err
+		}
+		return func(event model.AssembledEvent) string { return path.LookupString(event.Raw) }, nil
+	default:
+		return nil, fmt.Errorf("unknown key field %q", field)
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// Checker хранит состояние готовности компонентов сервиса
+type Checker struct {
+	consumerActive atomic.Bool
+	telegramReady  atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// SetConsumerActive отмечает, активна ли сессия consumer group
+func (c *Checker) SetConsumerActive(active bool) {
+	c.consumerActive.Store(active)
+}
+
+// SetTelegramReady отмечает, что токен бота проверен вызовом getMe
+func (c *Checker) SetTelegramReady(ready bool) {
+	c.telegramReady.Store(ready)
+}
+
+// Ready сообщает, готов ли сервис обрабатывать сообщения
+func (c *Checker) Ready() bool {
+	return c.consumerActive.Load() && c.telegramReady.Load()
+}
+
+type response struct {
+	Status string          `json:"status"`
+	Checks map[string]bool `json:"checks,omitempty"`
+}
+
+// LivenessHandler отвечает 200, пока процесс жив
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, response{Status: "ok"})
+	})
+}
+
+// ReadinessHandler отвечает 200, если сессия consumer group активна и токен бота проверен, иначе 503
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		resp := response{
+			Status: "ok",
+			Checks: map[string]bool{
+				"kafka_consumer_session": c.consumerActive.Load(),
+				"telegram_bot":           c.telegramReady.Load(),
+			},
+		}
+
+		code := http.StatusOK
+		if !c.Ready() {
+			resp.Status = "not ready"
+			code = http.StatusServiceUnavailable
+		}
+
+		writeJSON(w, code, resp)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
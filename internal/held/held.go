@@ -0,0 +1,116 @@
+package held
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// DefaultPath — файл хранилища по умолчанию
+const DefaultPath = "held.db"
+
+// notificationsBucket: идентификатор уведомления → уведомление в JSON
+var notificationsBucket = []byte("notifications")
+
+// Store хранит уведомления, отложенные до окончания тихих часов, в файле bbolt,
+// чтобы они не терялись при перезапуске
+type Store struct {
+	db *bolt.DB
+}
+
+// Open открывает хранилище отложенных уведомлений
+func Open(path string) (*Store, error) {
+	// Таймаут не даёт зависнуть, если файл занят другим процессом
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open held notifications store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(notificationsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init held notifications store %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Add сохраняет уведомление и возвращает присвоенный ему идентификатор
+func (s *Store) Add(notification model.HeldNotification) (uint64, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		notifications := tx.Bucket(notificationsBucket)
+
+		id, err := notifications.NextSequence()
+		if err != nil {
+			return err
+		}
+		notification.ID = id
+
+		return put(notifications, notification)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return notification.ID, nil
+}
+
+// List возвращает отложенные уведомления в порядке добавления
+func (s *Store) List() ([]model.HeldNotification, error) {
+	var notifications []model.HeldNotification
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(notificationsBucket).ForEach(func(_, value []byte) error {
+			var notification model.HeldNotification
+			if err := json.Unmarshal(value, &notification); err != nil {
+				return err
+			}
+			notifications = append(notifications, notification)
+			return nil
+		})
+	})
+
+	return notifications, err
+}
+
+// Save заменяет ранее сохранённое уведомление
+func (s *Store) Save(notification model.HeldNotification) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(notificationsBucket), notification)
+	})
+}
+
+// Delete удаляет уведомление
+func (s *Store) Delete(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(notificationsBucket).Delete(key(id))
+	})
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func put(bucket *bolt.Bucket, notification model.HeldNotification) error {
+	value, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put(key(notification.ID), value)
+}
+
+func key(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+
+	return key
+}
@@ -0,0 +1,73 @@
+package held
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "held.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	until := time.Date(2026, time.June, 11, 8, 0, 0, 0, time.UTC)
+	first := model.HeldNotification{
+		Rule:    "night",
+		ChatIDs: []int64{-1001, -1002},
+		Event:   model.AssembledEvent{EventUuid: "a", App: "billing"},
+		Kafka:   model.KafkaMeta{Topic: "events", Partition: 1, Offset: 42},
+		Message: "first",
+		Until:   until,
+	}
+	second := model.HeldNotification{Rule: "night", ChatIDs: []int64{-1001}, Message: "second", Until: until}
+
+	if first.ID, err = store.Add(first); err != nil {
+		t.Fatal(err)
+	}
+	if second.ID, err = store.Add(second); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID >= second.ID {
+		t.Fatalf("Add() ids = %d, %d, want increasing", first.ID, second.ID)
+	}
+
+	first.ChatIDs = []int64{-1002}
+	if err := store.Save(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(second.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Уведомления переживают перезапуск
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	got, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []model.HeldNotification{first}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+
+	third, err := store.Add(model.HeldNotification{Rule: "night"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third <= second.ID {
+		t.Errorf("Add() after reopen id = %d, want greater than %d", third, second.ID)
+	}
+}
@@ -0,0 +1,26 @@
+package httpauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Bearer пропускает к next только запросы с заголовком Authorization: Bearer <token>.
+// Пустой token запрещает все запросы
+func Bearer(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func authorized(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
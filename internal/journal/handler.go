@@ -0,0 +1,33 @@
+package journal
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/major1ink/simple-notification-telegram/internal/httpauth"
+)
+
+// Handler отвечает записями журнала о доставке события: GET /journal?event_uuid=<uuid>.
+// Запросы требуют заголовка Authorization: Bearer <token>
+func (j *Journal) Handler(token string) http.Handler {
+	return httpauth.Bearer(token, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eventUuid := r.URL.Query().Get("event_uuid")
+		if eventUuid == "" {
+			http.Error(w, "event_uuid is required", http.StatusBadRequest)
+			return
+		}
+
+		deliveries, err := j.Find(eventUuid)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if deliveries == nil {
+			http.Error(w, "event not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(deliveries)
+	}))
+}
@@ -0,0 +1,195 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// DefaultRetention — срок хранения записей по умолчанию
+const DefaultRetention = 7 * 24 * time.Hour
+
+// maxCleanupInterval — наибольший интервал удаления устаревших записей
+const maxCleanupInterval = time.Hour
+
+var (
+	// deliveriesBucket: время записи (unix nano) + порядковый номер → запись в JSON
+	deliveriesBucket = []byte("deliveries")
+	// eventsBucket — индекс по event_uuid: event_uuid + 0x00 + ключ записи → пусто
+	eventsBucket = []byte("events")
+)
+
+// Journal — журнал попыток доставки уведомлений в файле bbolt
+type Journal struct {
+	db        *bolt.DB
+	retention time.Duration
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// Open открывает журнал и запускает удаление записей старше retention
+func Open(path string, retention time.Duration) (*Journal, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	// Таймаут не даёт зависнуть, если файл занят другим процессом
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery journal %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{deliveriesBucket, eventsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init delivery journal %s: %w", path, err)
+	}
+
+	j := &Journal{
+		db:        db,
+		retention: retention,
+		done:      make(chan struct{}),
+	}
+
+	j.wg.Add(1)
+	go j.cleanup(min(retention, maxCleanupInterval))
+
+	return j, nil
+}
+
+// Record сохраняет запись о попытке доставки
+func (j *Journal) Record(delivery model.Delivery) error {
+	value, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket(deliveriesBucket)
+
+		seq, err := deliveries.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		key := make([]byte, 16)
+		binary.BigEndian.PutUint64(key, uint64(delivery.FinishedAt.UnixNano()))
+		binary.BigEndian.PutUint64(key[8:], seq)
+
+		if err := deliveries.Put(key, value); err != nil {
+			return err
+		}
+		if delivery.EventUuid == "" {
+			return nil
+		}
+
+		return tx.Bucket(eventsBucket).Put(eventKey(delivery.EventUuid, key), nil)
+	})
+}
+
+// Find возвращает записи о доставке события в порядке времени
+func (j *Journal) Find(eventUuid string) ([]model.Delivery, error) {
+	var deliveries []model.Delivery
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		records := tx.Bucket(deliveriesBucket)
+		prefix := eventKey(eventUuid, nil)
+
+		cursor := tx.Bucket(eventsBucket).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			value := records.Get(key[len(prefix):])
+			if value == nil {
+				continue
+			}
+
+			var delivery model.Delivery
+			if err := json.Unmarshal(value, &delivery); err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+
+	return deliveries, err
+}
+
+func (j *Journal) Close() error {
+	close(j.done)
+	j.wg.Wait()
+
+	return j.db.Close()
+}
+
+func (j *Journal) cleanup(interval time.Duration) {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			_ = j.deleteExpired()
+		}
+	}
+}
+
+// deleteExpired удаляет записи старше срока хранения вместе с их индексом
+func (j *Journal) deleteExpired() error {
+	border := make([]byte, 8)
+	binary.BigEndian.PutUint64(border, uint64(time.Now().Add(-j.retention).UnixNano()))
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket(deliveriesBucket)
+		events := tx.Bucket(eventsBucket)
+
+		// Ключи упорядочены по времени, поэтому устаревшие записи идут первыми
+		var records, index [][]byte
+		cursor := deliveries.Cursor()
+		for key, value := cursor.First(); key != nil && bytes.Compare(key[:8], border) < 0; key, value = cursor.Next() {
+			records = append(records, append([]byte(nil), key...))
+
+			var delivery model.Delivery
+			if json.Unmarshal(value, &delivery) == nil && delivery.EventUuid != "" {
+				index = append(index, eventKey(delivery.EventUuid, key))
+			}
+		}
+
+		for _, key := range records {
+			if err := deliveries.Delete(key); err != nil {
+				return err
+			}
+		}
+		for _, key := range index {
+			if err := events.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func eventKey(eventUuid string, recordKey []byte) []byte {
+	key := make([]byte, 0, len(eventUuid)+1+len(recordKey))
+	key = append(key, eventUuid...)
+	key = append(key, 0)
+
+	return append(key, recordKey...)
+}
@@ -9,6 +9,14 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// level — уровень основного логгера, меняется без пересоздания логгера
+var level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// SetLevel меняет уровень основного логгера
+func SetLevel(logLevel string) {
+	level.SetLevel(getLevel(logLevel))
+}
+
 func NewLog(
 	fileName string,
 ) (*zap.Logger, error) {
@@ -20,11 +28,12 @@ func NewLog(
 	cfgLogger.EncoderConfig.EncodeTime = customTimeEncoder
 	cfgLogger.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 
-	cfgLogger.Level.SetLevel(getLevel(config.AppConfig().Logger.GetLevel()))
-
 	if fileName == "error.log" {
 		cfgLogger.Level.SetLevel(zapcore.ErrorLevel)
 		cfgLogger.DisableStacktrace = false
+	} else {
+		SetLevel(config.AppConfig().Logger.GetLevel())
+		cfgLogger.Level = level
 	}
 
 	if config.AppConfig().Logger.GetLogMode() == "stdout" && fileName != "error.log" {
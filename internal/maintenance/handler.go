@@ -0,0 +1,90 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/httpauth"
+)
+
+// setRequest — тело POST /maintenance. Окончание задаётся временем until или длительностью duration
+type setRequest struct {
+	App      string    `json:"app"`
+	Until    time.Time `json:"until"`
+	Duration string    `json:"duration"`
+	Reason   string    `json:"reason"`
+}
+
+// Handler управляет окнами обслуживания. Запросы требуют заголовка Authorization: Bearer <token>.
+//
+//	GET /maintenance — активные окна
+//	POST /maintenance {"app": "...", "until": "<RFC3339>" | "duration": "2h", "reason": "..."} — задать окно
+//	DELETE /maintenance?app=<app> — удалить окно, заданное через API
+func (w *Windows) Handler(token string) http.Handler {
+	return httpauth.Bearer(token, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(rw, http.StatusOK, w.List(time.Now()))
+		case http.MethodPost:
+			w.handleSet(rw, r)
+		case http.MethodDelete:
+			app := r.URL.Query().Get("app")
+			if app == "" {
+				http.Error(rw, "app is required", http.StatusBadRequest)
+				return
+			}
+			if !w.Delete(app) {
+				http.Error(rw, "maintenance window set through API not found", http.StatusNotFound)
+				return
+			}
+			rw.WriteHeader(http.StatusNoContent)
+		default:
+			rw.Header().Set("Allow", "GET, POST, DELETE")
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func (w *Windows) handleSet(rw http.ResponseWriter, r *http.Request) {
+	var req setRequest
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, 1<<16)).Decode(&req); err != nil {
+		http.Error(rw, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.App == "" {
+		http.Error(rw, "app is required", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	until := req.Until
+	switch {
+	case req.Duration != "" && !until.IsZero():
+		http.Error(rw, "until and duration are mutually exclusive", http.StatusBadRequest)
+		return
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			http.Error(rw, "invalid duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		until = now.Add(duration)
+	}
+	if !until.After(now) {
+		http.Error(rw, "until must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, w.Set(Window{
+		App:    req.App,
+		Until:  until,
+		Reason: req.Reason,
+	}))
+}
+
+func writeJSON(rw http.ResponseWriter, status int, value any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(value)
+}
@@ -0,0 +1,89 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	const token = "secret"
+	w := New(nil)
+	handler := w.Handler(token)
+
+	do := func(method, target, body, auth string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		auth       string
+		wantStatus int
+	}{
+		{name: "no token", method: http.MethodGet, target: "/maintenance", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodGet, target: "/maintenance", auth: "Bearer other", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", method: http.MethodGet, target: "/maintenance", auth: token, wantStatus: http.StatusUnauthorized},
+		{name: "set by duration", method: http.MethodPost, target: "/maintenance", body: `{"app": "billing", "duration": "2h", "reason": "релиз"}`, wantStatus: http.StatusOK},
+		{name: "app required", method: http.MethodPost, target: "/maintenance", body: `{"duration": "2h"}`, wantStatus: http.StatusBadRequest},
+		{name: "until and duration", method: http.MethodPost, target: "/maintenance", body: `{"app": "a", "duration": "2h", "until": "2099-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "until in the past", method: http.MethodPost, target: "/maintenance", body: `{"app": "a", "until": "2000-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid duration", method: http.MethodPost, target: "/maintenance", body: `{"app": "a", "duration": "soon"}`, wantStatus: http.StatusBadRequest},
+		{name: "delete without app", method: http.MethodDelete, target: "/maintenance", wantStatus: http.StatusBadRequest},
+		{name: "delete unknown", method: http.MethodDelete, target: "/maintenance?app=orders", wantStatus: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodPut, target: "/maintenance", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := tt.auth
+			if auth == "" && tt.wantStatus != http.StatusUnauthorized {
+				auth = "Bearer " + token
+			}
+			if rec := do(tt.method, tt.target, tt.body, auth); rec.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.target, rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+
+	rec := do(http.MethodGet, "/maintenance", "", "Bearer "+token)
+	var windows []Window
+	if err := json.NewDecoder(rec.Body).Decode(&windows); err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 1 || windows[0].App != "billing" || windows[0].Source != SourceAPI {
+		t.Fatalf("GET /maintenance = %+v, want billing window from api", windows)
+	}
+	if until := time.Until(windows[0].Until); until < time.Hour || until > 2*time.Hour {
+		t.Errorf("window ends in %v, want about 2h", until)
+	}
+
+	if rec := do(http.MethodDelete, "/maintenance?app=billing", "", "Bearer "+token); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if _, ok := w.Muted("billing", time.Now()); ok {
+		t.Error("billing still muted after DELETE")
+	}
+}
+
+func TestHandlerEmptyTokenRejects(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/maintenance", nil)
+	r.Header.Set("Authorization", "Bearer ")
+	New(nil).Handler("").ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,115 @@
+package maintenance
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Window — окно обслуживания: уведомления сервиса App не отправляются до Until
+type Window struct {
+	App    string    `json:"app"`
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason,omitempty"`
+	// Source — откуда задано окно: config или api
+	Source string `json:"source"`
+}
+
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+// Windows хранит окна обслуживания из конфигурации и заданные через API.
+// Окна из API хранятся в памяти и не переживают перезапуск
+type Windows struct {
+	mu         sync.Mutex
+	configured []Window
+	api        map[string]Window
+}
+
+func New(configured []Window) *Windows {
+	w := &Windows{api: make(map[string]Window)}
+	w.Update(configured)
+
+	return w
+}
+
+// Update заменяет окна из конфигурации
+func (w *Windows) Update(configured []Window) {
+	windows := make([]Window, 0, len(configured))
+	for _, window := range configured {
+		window.Source = SourceConfig
+		windows = append(windows, window)
+	}
+
+	w.mu.Lock()
+	w.configured = windows
+	w.mu.Unlock()
+}
+
+// Set задаёт окно обслуживания сервиса через API, заменяя предыдущее
+func (w *Windows) Set(window Window) Window {
+	window.Source = SourceAPI
+
+	w.mu.Lock()
+	w.api[window.App] = window
+	w.mu.Unlock()
+
+	return window
+}
+
+// Delete удаляет окно сервиса, заданное через API. Окна из конфигурации не удаляются
+func (w *Windows) Delete(app string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.api[app]
+	delete(w.api, app)
+
+	return ok
+}
+
+// Muted возвращает активное в момент now окно обслуживания сервиса с самым поздним окончанием
+func (w *Windows) Muted(app string, now time.Time) (Window, bool) {
+	var (
+		muted Window
+		found bool
+	)
+	for _, window := range w.List(now) {
+		if window.App == app && (!found || window.Until.After(muted.Until)) {
+			muted, found = window, true
+		}
+	}
+
+	return muted, found
+}
+
+// List возвращает окна, активные в момент now. Истёкшие окна из API удаляются
+func (w *Windows) List(now time.Time) []Window {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var active []Window
+	for _, window := range w.configured {
+		if now.Before(window.Until) {
+			active = append(active, window)
+		}
+	}
+	for app, window := range w.api {
+		if !now.Before(window.Until) {
+			delete(w.api, app)
+			continue
+		}
+		active = append(active, window)
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].App != active[j].App {
+			return active[i].App < active[j].App
+		}
+		return active[i].Until.Before(active[j].Until)
+	})
+
+	return active
+}
@@ -0,0 +1,67 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowsMuted(t *testing.T) {
+	now := time.Date(2026, time.June, 10, 12, 0, 0, 0, time.UTC)
+	w := New([]Window{
+		{App: "billing", Until: now.Add(time.Hour), Reason: "config"},
+		{App: "expired", Until: now.Add(-time.Minute)},
+	})
+	w.Set(Window{App: "billing", Until: now.Add(2 * time.Hour), Reason: "api"})
+	w.Set(Window{App: "orders", Until: now.Add(time.Minute)})
+
+	tests := []struct {
+		name       string
+		app        string
+		now        time.Time
+		wantOK     bool
+		wantReason string
+	}{
+		{name: "latest window wins", app: "billing", now: now, wantOK: true, wantReason: "api"},
+		{name: "config window expired", app: "expired", now: now},
+		{name: "api window active", app: "orders", now: now, wantOK: true},
+		{name: "end is exclusive", app: "orders", now: now.Add(time.Minute)},
+		{name: "other app", app: "payments", now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, ok := w.Muted(tt.app, tt.now)
+			if ok != tt.wantOK {
+				t.Fatalf("Muted(%q) ok = %v, want %v", tt.app, ok, tt.wantOK)
+			}
+			if window.Reason != tt.wantReason {
+				t.Errorf("Muted(%q) reason = %q, want %q", tt.app, window.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestWindowsUpdateAndDelete(t *testing.T) {
+	now := time.Date(2026, time.June, 10, 12, 0, 0, 0, time.UTC)
+	w := New([]Window{{App: "billing", Until: now.Add(time.Hour)}})
+	w.Set(Window{App: "orders", Until: now.Add(time.Hour)})
+
+	// Окна из API не зависят от перезагрузки конфигурации
+	w.Update(nil)
+	if _, ok := w.Muted("billing", now); ok {
+		t.Error("billing muted after config windows removed")
+	}
+	if _, ok := w.Muted("orders", now); !ok {
+		t.Error("orders not muted after config reload")
+	}
+
+	if !w.Delete("orders") {
+		t.Error("Delete(orders) = false, want true")
+	}
+	if w.Delete("orders") {
+		t.Error("second Delete(orders) = true, want false")
+	}
+	if got := w.List(now); len(got) != 0 {
+		t.Errorf("List() = %+v, want empty", got)
+	}
+}
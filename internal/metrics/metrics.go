@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+const namespace = "notification"
+
+// Metrics — метрики потребления сообщений и доставки уведомлений
+type Metrics struct {
+	registry *prometheus.Registry
+
+	consumed     *prometheus.CounterVec
+	decoded      *prometheus.CounterVec
+	decodeFailed *prometheus.CounterVec
+	duplicates   *prometheus.CounterVec
+	muted        *prometheus.CounterVec
+	sent         *prometheus.CounterVec
+	sendFailed   *prometheus.CounterVec
+	sendDuration *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+	consumerLag  *prometheus.GaugeVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_consumed_total",
+			Help:      "Количество прочитанных сообщений kafka.",
+		}, []string{"topic"}),
+		decoded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_decoded_total",
+			Help:      "Количество успешно декодированных событий.",
+		}, []string{"topic", "app", "type_event"}),
+		decodeFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_decode_failed_total",
+			Help:      "Количество сообщений, которые не удалось декодировать.",
+		}, []string{"topic"}),
+		duplicates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_duplicate_total",
+			Help:      "Количество повторных событий, пропущенных дедупликацией.",
+		}, []string{"topic", "app", "type_event"}),
+		muted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_muted_total",
+			Help:      "Количество событий, не отправленных из-за окна обслуживания.",
+		}, []string{"topic", "app", "type_event"}),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "telegram_messages_sent_total",
+			Help:      "Количество сообщений, отправленных в Telegram.",
+		}, []string{"topic", "app", "type_event", "chat"}),
+		sendFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "telegram_messages_failed_total",
+			Help:      "Количество неудачных попыток отправки в Telegram.",
+		}, []string{"topic", "app", "type_event", "chat"}),
+		sendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "telegram_send_duration_seconds",
+			Help:      "Длительность запросов отправки в Telegram Bot API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "messages_in_flight",
+			Help:      "Количество сообщений kafka, обрабатываемых в данный момент.",
+		}),
+		consumerLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "consumer_lag",
+			Help:      "Отставание consumer group от high-water mark партиции.",
+		}, []string{"topic", "partition"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.consumed,
+		m.decoded,
+		m.decodeFailed,
+		m.duplicates,
+		m.muted,
+		m.sent,
+		m.sendFailed,
+		m.sendDuration,
+		m.inFlight,
+		m.consumerLag,
+	)
+
+	return m
+}
+
+// Handler возвращает HTTP обработчик для /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// eventLabels — метки события, заполняемые обработчиком после декодирования
+type eventLabels struct {
+	mu        sync.Mutex
+	topic     string
+	decoded   bool
+	duplicate bool
+	muted     bool
+	app       string
+	typeEvent string
+}
+
+type eventLabelsKey struct{}
+
+// SetEvent сохраняет в контексте сообщения сведения о декодированном событии.
+// Используется для меток счётчиков декодирования и отправки.
+func SetEvent(ctx context.Context, app, typeEvent string) {
+	labels, ok := ctx.Value(eventLabelsKey{}).(*eventLabels)
+	if !ok {
+		return
+	}
+
+	labels.mu.Lock()
+	defer labels.mu.Unlock()
+
+	labels.decoded = true
+	labels.app = app
+	labels.typeEvent = typeEvent
+}
+
+// SetDuplicate отмечает в контексте сообщения, что событие пропущено как повторное
+func SetDuplicate(ctx context.Context) {
+	labels, ok := ctx.Value(eventLabelsKey{}).(*eventLabels)
+	if !ok {
+		return
+	}
+
+	labels.mu.Lock()
+	defer labels.mu.Unlock()
+
+	labels.duplicate = true
+}
+
+// SetMuted отмечает в контексте сообщения, что событие не отправлено из-за окна обслуживания
+func SetMuted(ctx context.Context) {
+	labels, ok := ctx.Value(eventLabelsKey{}).(*eventLabels)
+	if !ok {
+		return
+	}
+
+	labels.mu.Lock()
+	defer labels.mu.Unlock()
+
+	labels.muted = true
+}
+
+func labelsFromContext(ctx context.Context) (topic, app, typeEvent string) {
+	labels, ok := ctx.Value(eventLabelsKey{}).(*eventLabels)
+	if !ok {
+		return "", "", ""
+	}
+
+	labels.mu.Lock()
+	defer labels.mu.Unlock()
+
+	return labels.topic, labels.app, labels.typeEvent
+}
+
+// Middleware считает прочитанные и декодированные сообщения, сообщения в обработке
+// и отставание от high-water mark партиции
+func (m *Metrics) Middleware() consumer.Middleware {
+	return func(next consumer.MessageHandler) consumer.MessageHandler {
+		return func(ctx context.Context, msg consumer.Message) error {
+			m.consumed.WithLabelValues(msg.Topic).Inc()
+			if msg.HighWaterMark > 0 {
+				m.consumerLag.
+					WithLabelValues(msg.Topic, strconv.FormatInt(int64(msg.Partition), 10)).
+					Set(float64(msg.HighWaterMark - msg.Offset - 1))
+			}
+
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			labels := &eventLabels{topic: msg.Topic}
+			err := next(context.WithValue(ctx, eventLabelsKey{}, labels), msg)
+
+			labels.mu.Lock()
+			defer labels.mu.Unlock()
+
+			if labels.decoded {
+				m.decoded.WithLabelValues(msg.Topic, labels.app, labels.typeEvent).Inc()
+			} else if ctx.Err() == nil {
+				m.decodeFailed.WithLabelValues(msg.Topic).Inc()
+			}
+			if labels.duplicate {
+				m.duplicates.WithLabelValues(msg.Topic, labels.app, labels.typeEvent).Inc()
+			}
+			if labels.muted {
+				m.muted.WithLabelValues(msg.Topic, labels.app, labels.typeEvent).Inc()
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	def "github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+type telegramClient struct {
+	next    def.TelegramClient
+	metrics *Metrics
+}
+
+// NewTelegramClient оборачивает клиент Telegram сбором метрик отправки
+func NewTelegramClient(next def.TelegramClient, metrics *Metrics) *telegramClient {
+	return &telegramClient{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (c *telegramClient) SendMessage(ctx context.Context, msg model.TelegramMessage) (int, error) {
+	start := time.Now()
+	messageID, err := c.next.SendMessage(ctx, msg)
+	c.observe(ctx, "sendMessage", msg.ChatID, start, err)
+
+	return messageID, err
+}
+
+func (c *telegramClient) SendDocument(ctx context.Context, doc model.TelegramDocument) (int, error) {
+	start := time.Now()
+	messageID, err := c.next.SendDocument(ctx, doc)
+	c.observe(ctx, "sendDocument", doc.ChatID, start, err)
+
+	return messageID, err
+}
+
+func (c *telegramClient) SendReply(ctx context.Context, msg model.TelegramMessage, replyTo int) (int, error) {
+	start := time.Now()
+	messageID, err := c.next.SendReply(ctx, msg, replyTo)
+	c.observe(ctx, "sendMessage", msg.ChatID, start, err)
+
+	return messageID, err
+}
+
+func (c *telegramClient) EditMessage(ctx context.Context, edit model.TelegramEdit) error {
+	start := time.Now()
+	err := c.next.EditMessage(ctx, edit)
+	c.observe(ctx, "editMessageText", edit.ChatID, start, err)
+
+	return err
+}
+
+func (c *telegramClient) observe(ctx context.Context, method string, chatID int64, start time.Time, err error) {
+	c.metrics.sendDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	topic, app, typeEvent := labelsFromContext(ctx)
+	chat := strconv.FormatInt(chatID, 10)
+	if err != nil {
+		c.metrics.sendFailed.WithLabelValues(topic, app, typeEvent, chat).Inc()
+		return
+	}
+	c.metrics.sent.WithLabelValues(topic, app, typeEvent, chat).Inc()
+}
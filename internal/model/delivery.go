@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// DeliveryStatus — итог попытки доставки уведомления в чат
+type DeliveryStatus string
+
+const (
+	DeliverySent   DeliveryStatus = "sent"
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Delivery — запись журнала о попытке доставки уведомления в чат
+type Delivery struct {
+	EventUuid string `json:"event_uuid"`
+	Binding   string `json:"binding,omitempty"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+
+	ChatID   int64 `json:"chat_id"`
+	ThreadID int   `json:"thread_id,omitempty"`
+	// MessageID — message_id первого отправленного сообщения,
+	// MessageIDs — всех частей, если сообщение разбито
+	MessageID  int   `json:"message_id,omitempty"`
+	MessageIDs []int `json:"message_ids,omitempty"`
+
+	Status DeliveryStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+
+	// EventTime — время kafka-сообщения, StartedAt и FinishedAt — начало и конец отправки
+	EventTime  time.Time `json:"event_time"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
@@ -1,8 +1,33 @@
 package model
 
+import "time"
+
 type AssembledEvent struct {
 	EventUuid string `json:"event_uuid"`
 	TypeEvent string `json:"type_event"`
 	App       string `json:"app"`
 	Message   string `json:"message"`
+	// Severity — уровень важности, необязательный
+	Severity Severity `json:"severity,omitempty"`
+
+	// Fields — все поля исходного сообщения для шаблонов (для декодеров со схемой)
+	Fields map[string]any `json:"-"`
+	// Raw — исходный документ целиком для шаблонов
+	Raw any `json:"-"`
+	// Time — время события из источника (например, атрибут time в CloudEvents)
+	Time time.Time `json:"-"`
+
+	Kafka KafkaMeta `json:"-"`
+}
+
+// KafkaMeta — метаданные kafka-сообщения, из которого получено событие
+type KafkaMeta struct {
+	// Binding — имя привязки topic, по которой декодировано сообщение
+	Binding   string
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Headers   map[string]string
+	Timestamp time.Time
 }
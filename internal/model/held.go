@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// HeldNotification — уведомление, отложенное до окончания тихих часов маршрута
+type HeldNotification struct {
+	ID uint64 `json:"id"`
+	// Rule — имя правила маршрутизации, по тихим часам которого отложено уведомление
+	Rule string `json:"rule"`
+	// ChatIDs — чаты, в которые уведомление ещё не доставлено
+	ChatIDs  []int64 `json:"chat_ids"`
+	ThreadID int     `json:"thread_id,omitempty"`
+
+	Event AssembledEvent `json:"event"`
+	// Kafka и EventTime сохраняют поля события, которые не попадают в его JSON
+	Kafka     KafkaMeta `json:"kafka"`
+	EventTime time.Time `json:"event_time,omitempty"`
+	// CorrelationKey — ключ корреляции, вычисленный при откладывании: исходный документ не сохраняется
+	CorrelationKey string `json:"correlation_key,omitempty"`
+
+	Message string `json:"message"`
+	Silent  bool   `json:"silent,omitempty"`
+	// Until — окончание тихих часов, после которого уведомление отправляется
+	Until time.Time `json:"until"`
+}
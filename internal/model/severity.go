@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Severity — уровень важности события. Нулевое значение — уровень не задан
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// Severities возвращает все уровни по возрастанию важности
+func Severities() []Severity {
+	return []Severity{SeverityDebug, SeverityInfo, SeverityWarning, SeverityError, SeverityCritical}
+}
+
+// ParseSeverity разбирает уровень без учёта регистра. Кроме полных имён
+// принимаются сокращения warn, err, crit и fatal
+func ParseSeverity(value string) (Severity, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return SeverityDebug, true
+	case "info":
+		return SeverityInfo, true
+	case "warning", "warn":
+		return SeverityWarning, true
+	case "error", "err":
+		return SeverityError, true
+	case "critical", "crit", "fatal":
+		return SeverityCritical, true
+	default:
+		return SeverityNone, false
+	}
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// MarshalText возвращает имя уровня
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText разбирает уровень. Неизвестное значение считается незаданным уровнем,
+// чтобы событие не отбрасывалось из-за опечатки в источнике
+func (s *Severity) UnmarshalText(text []byte) error {
+	*s, _ = ParseSeverity(string(text))
+	return nil
+}
+
+// UnmarshalJSON разбирает уровень из JSON строки. Значения других типов (числа, null)
+// считаются незаданным уровнем и не приводят к ошибке разбора события
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		*s = SeverityNone
+		return nil
+	}
+
+	return s.UnmarshalText([]byte(value))
+}
@@ -0,0 +1,52 @@
+package model
+
+// ParseMode — режим разметки текста сообщения Telegram
+type ParseMode string
+
+const (
+	ParseModePlain      ParseMode = ""
+	ParseModeMarkdown   ParseMode = "Markdown"
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+	ParseModeHTML       ParseMode = "HTML"
+)
+
+// TelegramMessage — сообщение для отправки в чат Telegram
+type TelegramMessage struct {
+	ChatID    int64
+	ThreadID  int
+	Text      string
+	ParseMode ParseMode
+	// DisableNotification — сообщение доставляется без звука
+	DisableNotification bool
+}
+
+// TelegramEdit — новый текст ранее отправленного сообщения
+type TelegramEdit struct {
+	ChatID    int64
+	MessageID int
+	Text      string
+	ParseMode ParseMode
+}
+
+// TelegramDocument — документ для отправки в чат Telegram
+type TelegramDocument struct {
+	ChatID    int64
+	ThreadID  int
+	Filename  string
+	Data      []byte
+	Caption   string
+	ParseMode ParseMode
+	// DisableNotification — документ доставляется без звука
+	DisableNotification bool
+}
+
+// SentMessage — отправленное в чат сообщение, которое можно изменить или на которое можно ответить
+type SentMessage struct {
+	ChatID    int64 `json:"chat_id"`
+	ThreadID  int   `json:"thread_id,omitempty"`
+	MessageID int   `json:"message_id"`
+	// Document — сообщение отправлено документом, его текст изменить нельзя
+	Document bool `json:"document,omitempty"`
+	// Digest — сводка отложенных на тихие часы уведомлений: её текст не заменяется, на неё отвечаем
+	Digest bool `json:"digest,omitempty"`
+}
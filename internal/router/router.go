@@ -0,0 +1,248 @@
+package router
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/schedule"
+)
+
+// Action — действие, выполняемое для события, попавшего под маршрут
+type Action string
+
+const (
+	ActionSend Action = "send"
+	ActionDrop Action = "drop"
+)
+
+// QuietAction — что делать с событием в тихие часы
+type QuietAction string
+
+const (
+	QuietSilent QuietAction = "silent" // Отправить без звука
+	QuietHold   QuietAction = "hold"   // Отложить и отправить пачкой после окончания тихих часов
+	QuietDrop   QuietAction = "drop"   // Не отправлять
+)
+
+// QuietHours — тихие часы маршрута
+type QuietHours struct {
+	Schedule schedule.Schedule
+	// Below — действие применяется к событиям с уровнем ниже (и без уровня); не задан — ко всем событиям
+	Below  model.Severity
+	Action QuietAction
+}
+
+// Applies сообщает, что событие с уровнем severity в момент t попадает под тихие часы,
+// и возвращает время их окончания
+func (q *QuietHours) Applies(severity model.Severity, t time.Time) (time.Time, bool) {
+	if q == nil || q.Below != model.SeverityNone && severity >= q.Below {
+		return time.Time{}, false
+	}
+
+	return q.Schedule.End(t)
+}
+
+// MatchType — способ сравнения значения поля с шаблоном
+type MatchType string
+
+const (
+	MatchExact MatchType = "exact"
+	MatchGlob  MatchType = "glob"
+	MatchRegex MatchType = "regex"
+)
+
+// Поля события, по которым можно строить условия.
+// Заголовки kafka задаются как "header:<имя>".
+const (
+	FieldApp       = "app"
+	FieldTypeEvent = "type_event"
+	FieldKey       = "key"
+	FieldTopic     = "topic"
+	FieldBinding   = "binding"
+	FieldSeverity  = "severity"
+	FieldHeader    = "header:"
+)
+
+// Condition — условие совпадения одного поля события
+type Condition struct {
+	Field string
+	Type  MatchType
+	Value string
+}
+
+// Route — куда отправлять событие
+type Route struct {
+	Action   Action
+	ChatIDs  []int64
+	ThreadID int
+	// MinSeverity — события с уровнем ниже не отправляются. События без уровня не фильтруются
+	MinSeverity model.Severity
+	QuietHours  *QuietHours
+}
+
+// Allows сообщает, проходит ли событие с уровнем severity фильтр маршрута
+func (r Route) Allows(severity model.Severity) bool {
+	return severity == model.SeverityNone || severity >= r.MinSeverity
+}
+
+// Rule — правило маршрутизации. Событие попадает под правило,
+// если выполнены все его условия.
+type Rule struct {
+	Name       string
+	Conditions []Condition
+	Route      Route
+}
+
+type matcher func(event model.AssembledEvent) bool
+
+type compiledRule struct {
+	name     string
+	matchers []matcher
+	route    Route
+}
+
+type table struct {
+	rules []compiledRule
+	def   Route
+}
+
+// Router выбирает маршрут для события по упорядоченному списку правил.
+// Первое совпавшее правило определяет маршрут, иначе используется маршрут по умолчанию.
+type Router struct {
+	mu    sync.RWMutex
+	table table
+}
+
+// New создаёт маршрутизатор из списка правил и маршрута по умолчанию
+func New(rules []Rule, def Route) (*Router, error) {
+	r := &Router{}
+	if err := r.Update(rules, def); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Update атомарно заменяет правила маршрутизации
+func (r *Router) Update(rules []Rule, def Route) error {
+	t := table{def: normalizeRoute(def)}
+
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		compiled := compiledRule{
+			name:  name,
+			route: normalizeRoute(rule.Route),
+		}
+		for _, cond := range rule.Conditions {
+			m, err := compileCondition(cond)
+			if err != nil {
+				return fmt.Errorf("routing rule %s: %w", name, err)
+			}
+			compiled.matchers = append(compiled.matchers, m)
+		}
+
+		t.rules = append(t.rules, compiled)
+	}
+
+	r.mu.Lock()
+	r.table = t
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Route возвращает имя совпавшего правила ("default" для маршрута по умолчанию) и маршрут
+func (r *Router) Route(event model.AssembledEvent) (string, Route) {
+	r.mu.RLock()
+	t := r.table
+	r.mu.RUnlock()
+
+	for _, rule := range t.rules {
+		if rule.match(event) {
+			return rule.name, rule.route
+		}
+	}
+
+	return "default", t.def
+}
+
+func (c compiledRule) match(event model.AssembledEvent) bool {
+	for _, m := range c.matchers {
+		if !m(event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeRoute(route Route) Route {
+	if route.Action == "" {
+		route.Action = ActionSend
+	}
+
+	return route
+}
+
+func compileCondition(cond Condition) (matcher, error) {
+	extract, err := fieldExtractor(cond.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cond.Type {
+	case MatchExact, "":
+		return func(event model.AssembledEvent) bool {
+			return extract(event) == cond.Value
+		}, nil
+	case MatchGlob:
+		if _, err := path.Match(cond.Value, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", cond.Value, err)
+		}
+		return func(event model.AssembledEvent) bool {
+			ok, _ := path.Match(cond.Value, extract(event))
+			return ok
+		}, nil
+	case MatchRegex:
+		re, err := regexp.Compile(cond.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", cond.Value, err)
+		}
+		return func(event model.AssembledEvent) bool {
+			return re.MatchString(extract(event))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown match type %q", cond.Type)
+	}
+}
+
+func fieldExtractor(field string) (func(event model.AssembledEvent) string, error) {
+	switch {
+	case field == FieldApp:
+		return func(event model.AssembledEvent) string { return event.App }, nil
+	case field == FieldTypeEvent:
+		return func(event model.AssembledEvent) string { return event.TypeEvent }, nil
+	case field == FieldKey:
+		return func(event model.AssembledEvent) string { return event.Kafka.Key }, nil
+	case field == FieldTopic:
+		return func(event model.AssembledEvent) string { return event.Kafka.Topic }, nil
+	case field == FieldBinding:
+		return func(event model.AssembledEvent) string { return event.Kafka.Binding }, nil
+	case field == FieldSeverity:
+		return func(event model.AssembledEvent) string { return event.Severity.String() }, nil
+	case strings.HasPrefix(field, FieldHeader) && len(field) > len(FieldHeader):
+		name := strings.TrimPrefix(field, FieldHeader)
+		return func(event model.AssembledEvent) string { return event.Kafka.Headers[name] }, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+}
@@ -0,0 +1,95 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/schedule"
+)
+
+func TestQuietHoursApplies(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	night := schedule.Schedule{
+		Location: moscow,
+		Periods:  []schedule.Period{{From: 22 * time.Hour, To: 8 * time.Hour}},
+	}
+
+	tests := []struct {
+		name     string
+		quiet    *QuietHours
+		severity model.Severity
+		t        time.Time
+		want     time.Time
+		wantOK   bool
+	}{
+		{
+			name:  "no quiet hours",
+			quiet: nil,
+			t:     time.Date(2026, time.June, 10, 23, 0, 0, 0, moscow),
+		},
+		{
+			name:   "after midnight in route time zone",
+			quiet:  &QuietHours{Schedule: night},
+			t:      time.Date(2026, time.June, 10, 21, 30, 0, 0, time.UTC), // 00:30 в Москве
+			want:   time.Date(2026, time.June, 11, 8, 0, 0, 0, moscow),
+			wantOK: true,
+		},
+		{
+			name:   "evening elsewhere is night in route time zone",
+			quiet:  &QuietHours{Schedule: night},
+			t:      time.Date(2026, time.June, 10, 20, 0, 0, 0, time.FixedZone("UTC-3", -3*3600)), // 02:00 в Москве следующего дня
+			want:   time.Date(2026, time.June, 11, 8, 0, 0, 0, moscow),
+			wantOK: true,
+		},
+		{
+			name:  "outside quiet hours",
+			quiet: &QuietHours{Schedule: night},
+			t:     time.Date(2026, time.June, 10, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:     "severity below threshold",
+			quiet:    &QuietHours{Schedule: night, Below: model.SeverityError},
+			severity: model.SeverityWarning,
+			t:        time.Date(2026, time.June, 10, 23, 0, 0, 0, moscow),
+			want:     time.Date(2026, time.June, 11, 8, 0, 0, 0, moscow),
+			wantOK:   true,
+		},
+		{
+			name:     "severity at threshold",
+			quiet:    &QuietHours{Schedule: night, Below: model.SeverityError},
+			severity: model.SeverityError,
+			t:        time.Date(2026, time.June, 10, 23, 0, 0, 0, moscow),
+		},
+		{
+			name:   "event without severity below threshold",
+			quiet:  &QuietHours{Schedule: night, Below: model.SeverityError},
+			t:      time.Date(2026, time.June, 10, 23, 0, 0, 0, moscow),
+			want:   time.Date(2026, time.June, 11, 8, 0, 0, 0, moscow),
+			wantOK: true,
+		},
+		{
+			name:     "no threshold applies to all severities",
+			quiet:    &QuietHours{Schedule: night},
+			severity: model.SeverityCritical,
+			t:        time.Date(2026, time.June, 10, 23, 0, 0, 0, moscow),
+			want:     time.Date(2026, time.June, 11, 8, 0, 0, 0, moscow),
+			wantOK:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.quiet.Applies(tt.severity, tt.t)
+			if ok != tt.wantOK {
+				t.Fatalf("Applies() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
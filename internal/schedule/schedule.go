@@ -0,0 +1,134 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// База часовых поясов встроена в бинарник: в минимальных образах её может не быть
+	_ "time/tzdata"
+)
+
+// Period — интервал времени суток в заданные дни недели. Если To не позже From,
+// интервал заканчивается на следующий день (например, 22:00–08:00)
+type Period struct {
+	Days []time.Weekday // Дни начала интервала; пусто — каждый день
+	From time.Duration  // Начало от полуночи
+	To   time.Duration  // Конец от полуночи, до 24:00
+}
+
+// Schedule — набор интервалов в часовом поясе Location
+type Schedule struct {
+	Location *time.Location
+	Periods  []Period
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseWeekday разбирает день недели: mon или monday, без учёта регистра
+func ParseWeekday(value string) (time.Weekday, error) {
+	day, ok := weekdays[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q", value)
+	}
+
+	return day, nil
+}
+
+// ParseClock разбирает время суток HH:MM (от 00:00 до 24:00) в смещение от полуночи
+func ParseClock(value string) (time.Duration, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if errH != nil || errM != nil || len(minutes) != 2 || h < 0 || m < 0 || m > 59 || h > 24 || h == 24 && m > 0 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// End возвращает конец интервала расписания, в который попадает t.
+// Смежные интервалы (например, 22:00–24:00 и 00:00–08:00) объединяются
+func (s Schedule) End(t time.Time) (time.Time, bool) {
+	end, ok := s.window(t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// Ограничение защищает от бесконечного цикла при расписании на всю неделю
+	for i := 0; i < 8*len(s.Periods); i++ {
+		next, ok := s.window(end)
+		if !ok || !next.After(end) {
+			break
+		}
+		end = next
+	}
+
+	return end, true
+}
+
+// window возвращает наибольший конец интервала, содержащего t
+func (s Schedule) window(t time.Time) (time.Time, bool) {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	var (
+		end   time.Time
+		found bool
+	)
+	for _, period := range s.Periods {
+		// Интервал мог начаться накануне и продолжаться после полуночи
+		for _, shift := range []int{0, -1} {
+			day := time.Date(t.Year(), t.Month(), t.Day()+shift, 0, 0, 0, 0, loc)
+			if !period.on(day.Weekday()) {
+				continue
+			}
+
+			start := at(day, period.From)
+			finish := at(day, period.To)
+			if period.To <= period.From {
+				finish = at(day.AddDate(0, 0, 1), period.To)
+			}
+
+			if !t.Before(start) && t.Before(finish) && finish.After(end) {
+				end, found = finish, true
+			}
+		}
+	}
+
+	return end, found
+}
+
+func (p Period) on(day time.Weekday) bool {
+	if len(p.Days) == 0 {
+		return true
+	}
+	for _, d := range p.Days {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// at возвращает время суток offset в день day по часам пояса, а не через сложение длительностей,
+// чтобы переход на летнее время не сдвигал границы
+func at(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+}
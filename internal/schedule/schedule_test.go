@@ -0,0 +1,196 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "00:00", want: 0},
+		{value: "08:30", want: 8*time.Hour + 30*time.Minute},
+		{value: "7:05", want: 7*time.Hour + 5*time.Minute},
+		{value: "24:00", want: 24 * time.Hour},
+		{value: "24:01", wantErr: true},
+		{value: "25:00", wantErr: true},
+		{value: "12:60", wantErr: true},
+		{value: "12:5", wantErr: true},
+		{value: "-1:00", wantErr: true},
+		{value: "1200", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseClock(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClock(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{value: "mon", want: time.Monday},
+		{value: "Sunday", want: time.Sunday},
+		{value: " SAT ", want: time.Saturday},
+		{value: "mo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseWeekday(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekday(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWeekday(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleEnd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	night := Period{From: 22 * time.Hour, To: 8 * time.Hour}
+	weekend := []Period{
+		{Days: []time.Weekday{time.Friday}, From: 18 * time.Hour, To: 24 * time.Hour},
+		{Days: []time.Weekday{time.Saturday, time.Sunday}, From: 0, To: 24 * time.Hour},
+		{Days: []time.Weekday{time.Monday}, From: 0, To: 9 * time.Hour},
+	}
+
+	tests := []struct {
+		name    string
+		periods []Period
+		t       time.Time
+		want    time.Time
+		wantOK  bool
+	}{
+		{
+			name:    "overnight before midnight",
+			periods: []Period{night},
+			t:       at(2026, time.June, 10, 23, 0),
+			want:    at(2026, time.June, 11, 8, 0),
+			wantOK:  true,
+		},
+		{
+			name:    "overnight after midnight",
+			periods: []Period{night},
+			t:       at(2026, time.June, 11, 7, 59),
+			want:    at(2026, time.June, 11, 8, 0),
+			wantOK:  true,
+		},
+		{
+			name:    "start is inclusive",
+			periods: []Period{night},
+			t:       at(2026, time.June, 10, 22, 0),
+			want:    at(2026, time.June, 11, 8, 0),
+			wantOK:  true,
+		},
+		{
+			name:    "end is exclusive",
+			periods: []Period{night},
+			t:       at(2026, time.June, 11, 8, 0),
+		},
+		{
+			name:    "outside period",
+			periods: []Period{night},
+			t:       at(2026, time.June, 11, 12, 0),
+		},
+		{
+			// 29 марта 2026 часы переводятся с 02:00 на 03:00: ночь короче на час
+			name:    "overnight across spring DST change",
+			periods: []Period{night},
+			t:       at(2026, time.March, 28, 23, 0),
+			want:    time.Date(2026, time.March, 29, 6, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			// 25 октября 2026 часы переводятся с 03:00 на 02:00: ночь длиннее на час
+			name:    "overnight across autumn DST change",
+			periods: []Period{night},
+			t:       at(2026, time.October, 24, 23, 0),
+			want:    time.Date(2026, time.October, 25, 7, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "inside repeated hour of autumn DST change",
+			periods: []Period{night},
+			t:       time.Date(2026, time.October, 25, 0, 30, 0, 0, time.UTC),
+			want:    time.Date(2026, time.October, 25, 7, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "overnight period continues on next day only",
+			periods: []Period{{Days: []time.Weekday{time.Friday}, From: 22 * time.Hour, To: 8 * time.Hour}},
+			t:       at(2026, time.June, 13, 7, 0), // суббота
+			want:    at(2026, time.June, 13, 8, 0),
+			wantOK:  true,
+		},
+		{
+			name:    "overnight period not started on previous day",
+			periods: []Period{{Days: []time.Weekday{time.Friday}, From: 22 * time.Hour, To: 8 * time.Hour}},
+			t:       at(2026, time.June, 14, 7, 0), // воскресенье
+		},
+		{
+			name:    "adjacent periods are chained",
+			periods: weekend,
+			t:       at(2026, time.June, 12, 20, 0), // пятница
+			want:    at(2026, time.June, 15, 9, 0),
+			wantOK:  true,
+		},
+		{
+			name:    "chained periods across DST change",
+			periods: weekend,
+			t:       at(2026, time.March, 27, 20, 0), // пятница
+			want:    at(2026, time.March, 30, 9, 0),
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Schedule{Location: berlin, Periods: tt.periods}
+
+			got, ok := s.End(tt.t)
+			if ok != tt.wantOK {
+				t.Fatalf("End(%v) ok = %v, want %v", tt.t, ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("End(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleEndWholeWeek(t *testing.T) {
+	s := Schedule{Periods: []Period{{From: 0, To: 24 * time.Hour}}}
+	now := time.Date(2026, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+	end, ok := s.End(now)
+	if !ok {
+		t.Fatal("End() ok = false, want true")
+	}
+	if !end.After(now) {
+		t.Errorf("End() = %v, want time after %v", end, now)
+	}
+}
@@ -5,27 +5,27 @@ import (
 
 	"go.uber.org/zap"
 
-	kafkaConverter "github.com/major1ink/simple-notification-telegram/internal/converter/kafka"
+	"github.com/major1ink/simple-notification-telegram/internal/binding"
 	def "github.com/major1ink/simple-notification-telegram/internal/service"
 	"github.com/major1ink/simple-notification-telegram/pkg/kafka"
 )
 
 type service struct {
 	consumer        kafka.Consumer
-	decoder         kafkaConverter.OrderAssembledDecoder
+	bindings        *binding.Bindings
 	telegramService def.TelegramService
 	logger          *zap.Logger
 }
 
 func NewService(
 	consumer kafka.Consumer,
-	decoder kafkaConverter.OrderAssembledDecoder,
+	bindings *binding.Bindings,
 	telegramService def.TelegramService,
 	logger *zap.Logger,
 ) *service {
 	return &service{
 		consumer:        consumer,
-		decoder:         decoder,
+		bindings:        bindings,
 		telegramService: telegramService,
 		logger:          logger,
 	}
@@ -2,18 +2,58 @@ package consumer
 
 import (
 	"context"
+	"errors"
 
 	"go.uber.org/zap"
 
+	httpClient "github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/converter/kafka/decoder"
+	"github.com/major1ink/simple-notification-telegram/internal/metrics"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
 	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
 )
 
 func (s *service) Handler(ctx context.Context, msg consumer.Message) error {
-	event, err := s.decoder.DecodeAssembled(msg.Value)
+	b := s.bindings.Match(msg.Topic)
+
+	event, err := b.Decoder.Decode(ctx, msg)
 	if err != nil {
-		s.logger.Error("Failed to decode assembled event", zap.Error(err))
-		return err
+		s.logger.Error("Failed to decode assembled event",
+			zap.String("topic", msg.Topic),
+			zap.String("binding", b.Name),
+			zap.Error(err),
+		)
+		// Недоступность реестра схем временная — сообщение обрабатывается повторно
+		if errors.Is(err, decoder.ErrSchemaUnavailable) {
+			return err
+		}
+		return consumer.Permanent(err)
+	}
+
+	metrics.SetEvent(ctx, event.App, event.TypeEvent)
+
+	event.Kafka = model.KafkaMeta{
+		Binding:   b.Name,
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Headers:   make(map[string]string, len(msg.Headers)),
+		Timestamp: msg.Timestamp,
+	}
+	for k, v := range msg.Headers {
+		event.Kafka.Headers[k] = string(v)
+	}
+	if b.SeverityHeader != "" {
+		if severity, ok := model.ParseSeverity(event.Kafka.Headers[b.SeverityHeader]); ok {
+			event.Severity = severity
+		}
+	}
+
+	err = s.telegramService.SendAssembledNotification(ctx, event)
+	if errors.Is(err, httpClient.ErrRejected) {
+		return consumer.Permanent(err)
 	}
 
-	return s.telegramService.SendAssembledNotification(ctx, event)
+	return err
 }
@@ -0,0 +1,64 @@
+package dedup
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/major1ink/simple-notification-telegram/internal/dedup"
+	"github.com/major1ink/simple-notification-telegram/internal/eventkey"
+	"github.com/major1ink/simple-notification-telegram/internal/metrics"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	def "github.com/major1ink/simple-notification-telegram/internal/service"
+)
+
+// service пропускает события, уже доставленные в пределах TTL.
+// Ключ запоминается только после успешной отправки, чтобы неудачные попытки повторялись.
+type service struct {
+	next   def.TelegramService
+	store  dedup.Store
+	key    eventkey.Func
+	logger *zap.Logger
+}
+
+func NewService(next def.TelegramService, store dedup.Store, key eventkey.Func, logger *zap.Logger) *service {
+	return &service{
+		next:   next,
+		store:  store,
+		key:    key,
+		logger: logger,
+	}
+}
+
+func (s *service) SendAssembledNotification(ctx context.Context, event model.AssembledEvent) error {
+	key := s.key(event)
+	if key == "" {
+		return s.next.SendAssembledNotification(ctx, event)
+	}
+
+	seen, err := s.store.Seen(key)
+	if err != nil {
+		// Недоступное хранилище не должно останавливать доставку
+		s.logger.Error("Failed to check event for duplicate", zap.String("key", key), zap.Error(err))
+	}
+	if seen {
+		metrics.SetDuplicate(ctx)
+		s.logger.Debug("Duplicate event skipped",
+			zap.String("key", key),
+			zap.String("topic", event.Kafka.Topic),
+			zap.Int32("partition", event.Kafka.Partition),
+			zap.Int64("offset", event.Kafka.Offset),
+		)
+		return nil
+	}
+
+	if err := s.next.SendAssembledNotification(ctx, event); err != nil {
+		return err
+	}
+
+	if err := s.store.Add(key); err != nil {
+		s.logger.Error("Failed to remember delivered event", zap.String("key", key), zap.Error(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/major1ink/simple-notification-telegram/internal/maintenance"
+	"github.com/major1ink/simple-notification-telegram/internal/metrics"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	def "github.com/major1ink/simple-notification-telegram/internal/service"
+)
+
+// service не отправляет уведомления сервисов, для которых действует окно обслуживания
+type service struct {
+	next    def.TelegramService
+	windows *maintenance.Windows
+	logger  *zap.Logger
+}
+
+func NewService(next def.TelegramService, windows *maintenance.Windows, logger *zap.Logger) *service {
+	return &service{
+		next:    next,
+		windows: windows,
+		logger:  logger,
+	}
+}
+
+func (s *service) SendAssembledNotification(ctx context.Context, event model.AssembledEvent) error {
+	window, ok := s.windows.Muted(event.App, time.Now())
+	if !ok {
+		return s.next.SendAssembledNotification(ctx, event)
+	}
+
+	metrics.SetMuted(ctx)
+	s.logger.Debug("Event muted by maintenance window",
+		zap.String("app", event.App),
+		zap.String("event_uuid", event.EventUuid),
+		zap.Time("until", window.Until),
+		zap.String("reason", window.Reason),
+	)
+
+	return nil
+}
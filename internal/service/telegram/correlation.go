@@ -0,0 +1,183 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// CorrelationMode — способ обновления исходного сообщения инцидента
+type CorrelationMode string
+
+const (
+	// CorrelationEdit — текст исходного сообщения заменяется новым
+	CorrelationEdit CorrelationMode = "edit"
+	// CorrelationReply — новое сообщение отправляется ответом на исходное
+	CorrelationReply CorrelationMode = "reply"
+)
+
+// CorrelationStore хранит сообщения, отправленные по ключу корреляции
+type CorrelationStore interface {
+	Get(key string) ([]model.SentMessage, error)
+	Put(key string, messages []model.SentMessage) error
+}
+
+// Correlation — параметры обновления сообщений для событий одного инцидента
+type Correlation struct {
+	Key   func(event model.AssembledEvent) string
+	Mode  CorrelationMode
+	Store CorrelationStore
+}
+
+// SetCorrelation включает обновление ранее отправленных сообщений по ключу корреляции
+func (s *service) SetCorrelation(c Correlation) {
+	s.correlation = c
+}
+
+// incident возвращает ключ корреляции события и ранее отправленные по нему сообщения
+func (s *service) incident(event model.AssembledEvent) (string, []model.SentMessage) {
+	if s.correlation.Store == nil {
+		return "", nil
+	}
+
+	key := s.correlation.Key(event)
+
+	return key, s.previous(key)
+}
+
+// previous возвращает сообщения, ранее отправленные по ключу корреляции
+func (s *service) previous(key string) []model.SentMessage {
+	if s.correlation.Store == nil || key == "" {
+		return nil
+	}
+
+	previous, err := s.correlation.Store.Get(key)
+	if err != nil {
+		s.logger.Warn("Failed to load incident messages, sending new message",
+			zap.String("correlation_key", key),
+			zap.Error(err),
+		)
+	}
+
+	return previous
+}
+
+// remember сохраняет сообщения инцидента, заменяя прежние сообщения в тех же чатах
+func (s *service) remember(key string, previous, sent []model.SentMessage) {
+	if key == "" || len(sent) == 0 {
+		return
+	}
+
+	messages := append([]model.SentMessage(nil), sent...)
+	for _, message := range previous {
+		if _, ok := findMessage(sent, message.ChatID); !ok {
+			messages = append(messages, message)
+		}
+	}
+
+	if err := s.correlation.Store.Put(key, messages); err != nil {
+		s.logger.Warn("Failed to remember incident messages",
+			zap.String("correlation_key", key),
+			zap.Error(err),
+		)
+	}
+}
+
+// deliver отправляет сообщение в чат. Если по инциденту в этом чате уже есть сообщение,
+// оно изменяется или получает ответ; иначе отправляется новое сообщение, которое
+// возвращается для запоминания. sent — части сообщения, отправленные предыдущей попыткой
+func (s *service) deliver(
+	ctx context.Context,
+	chatID int64,
+	threadID int,
+	event model.AssembledEvent,
+	message string,
+	silent bool,
+	previous []model.SentMessage,
+	sent []int,
+) ([]int, *model.SentMessage, error) {
+	if original, ok := findMessage(previous, chatID); ok {
+		messageIDs, err := s.update(ctx, original, event, message, silent)
+		if !errors.Is(err, http.ErrRejected) {
+			return messageIDs, nil, err
+		}
+
+		// Исходное сообщение удалено или недоступно — инцидент начинается с нового сообщения
+		s.logger.Warn("Failed to update incident message, sending new message",
+			zap.Int64("chat_id", chatID),
+			zap.Int("message_id", original.MessageID),
+			zap.Error(err),
+		)
+	}
+
+	messageIDs, err := s.send(ctx, chatID, threadID, event, message, silent, sent)
+	if err != nil || len(messageIDs) == 0 {
+		return messageIDs, nil, err
+	}
+
+	return messageIDs, &model.SentMessage{
+		ChatID:    chatID,
+		ThreadID:  threadID,
+		MessageID: messageIDs[0],
+		Document:  s.asDocument(message),
+	}, nil
+}
+
+// update изменяет исходное сообщение инцидента или отвечает на него.
+// К тексту добавляется строка статуса; текст документа изменить нельзя, а сводку тихих часов
+// нельзя заменять текстом одного события, поэтому на них отвечаем.
+func (s *service) update(
+	ctx context.Context,
+	original model.SentMessage,
+	event model.AssembledEvent,
+	message string,
+	silent bool,
+) ([]int, error) {
+	status, err := s.renderer.RenderStatus(event)
+	if err != nil {
+		return nil, err
+	}
+
+	mk := markupFor(s.cfg.ParseMode)
+	text := headPart(message, maxMessageLength-utf16Len(status)-2, mk) + "\n\n" + status
+
+	if s.correlation.Mode != CorrelationReply && !original.Document && !original.Digest {
+		err := s.telegramClient.EditMessage(ctx, model.TelegramEdit{
+			ChatID:    original.ChatID,
+			MessageID: original.MessageID,
+			Text:      text,
+			ParseMode: mk.parseMode,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []int{original.MessageID}, nil
+	}
+
+	messageID, err := s.telegramClient.SendReply(ctx, model.TelegramMessage{
+		ChatID:              original.ChatID,
+		ThreadID:            original.ThreadID,
+		Text:                text,
+		ParseMode:           mk.parseMode,
+		DisableNotification: silent,
+	}, original.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []int{messageID}, nil
+}
+
+func findMessage(messages []model.SentMessage, chatID int64) (model.SentMessage, bool) {
+	for _, message := range messages {
+		if message.ChatID == chatID {
+			return message, true
+		}
+	}
+
+	return model.SentMessage{}, false
+}
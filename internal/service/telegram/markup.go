@@ -0,0 +1,369 @@
+package telegram
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// markup описывает особенности режима разметки: экранирование и разбор сущностей
+type markup struct {
+	parseMode model.ParseMode
+	escape    func(string) string
+	newState  func() markupState
+}
+
+var markups = map[model.ParseMode]markup{
+	model.ParseModePlain: {
+		parseMode: model.ParseModePlain,
+		escape:    func(s string) string { return s },
+		newState:  func() markupState { return plainState{} },
+	},
+	model.ParseModeMarkdown: {
+		parseMode: model.ParseModeMarkdown,
+		escape:    escapeMarkdownLegacy,
+		newState:  func() markupState { return &markdownState{} },
+	},
+	model.ParseModeMarkdownV2: {
+		parseMode: model.ParseModeMarkdownV2,
+		escape:    escapeMarkdownV2,
+		newState:  func() markupState { return &markdownV2State{} },
+	},
+	model.ParseModeHTML: {
+		parseMode: model.ParseModeHTML,
+		escape:    html.EscapeString,
+		newState:  func() markupState { return &htmlState{} },
+	},
+}
+
+// markupFor возвращает описание режима разметки (для неизвестного — plain)
+func markupFor(mode model.ParseMode) markup {
+	if m, ok := markups[mode]; ok {
+		return m
+	}
+
+	return markups[model.ParseModePlain]
+}
+
+var markdownLegacyReplacer = strings.NewReplacer(
+	"_", "\\_",
+	"*", "\\*",
+	"`", "\\`",
+	"[", "\\[",
+)
+
+// escapeMarkdownLegacy экранирует служебные символы legacy Markdown
+func escapeMarkdownLegacy(s string) string {
+	return markdownLegacyReplacer.Replace(s)
+}
+
+// markdownV2Reserved — символы, которые в MarkdownV2 необходимо экранировать вне сущностей
+const markdownV2Reserved = "_*[]()~`>#+-=|{}.!\\"
+
+// escapeMarkdownV2 экранирует служебные символы MarkdownV2
+func escapeMarkdownV2(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Reserved, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// plainState — текст без разметки, резать можно в любом месте
+type plainState struct{}
+
+func (plainState) next(text string, i int) int {
+	_, size := utf8.DecodeRuneInString(text[i:])
+	return i + size
+}
+
+func (plainState) balanced() bool  { return true }
+func (plainState) atomic() bool    { return false }
+func (plainState) closers() string { return "" }
+func (plainState) openers() string { return "" }
+
+// markdownState разбирает legacy Markdown Telegram: *bold*, _italic_, `code`, ```pre```, [text](url)
+type markdownState struct {
+	open string
+	link int // 0 — вне ссылки, 1 — текст ссылки, 2 — после "]", 3 — url
+}
+
+func (m *markdownState) next(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+
+	switch m.open {
+	case "```":
+		if strings.HasPrefix(text[i:], "```") {
+			m.open = ""
+			return i + 3
+		}
+		return i + size
+	case "`":
+		if r == '`' {
+			m.open = ""
+		}
+		return i + size
+	}
+
+	switch m.link {
+	case 1:
+		if r == ']' {
+			m.link = 2
+		}
+		return i + size
+	case 2:
+		if r == '(' {
+			m.link = 3
+			return i + size
+		}
+		m.link = 0
+	case 3:
+		if r == ')' {
+			m.link = 0
+		}
+		return i + size
+	}
+
+	switch {
+	case r == '\\' && m.open == "" && i+size < len(text):
+		_, escaped := utf8.DecodeRuneInString(text[i+size:])
+		return i + size + escaped
+	case strings.HasPrefix(text[i:], "```") && m.open == "":
+		m.open = "```"
+		return i + 3
+	case r == '`' || r == '*' || r == '_':
+		if m.open == string(r) {
+			m.open = ""
+		} else if m.open == "" {
+			m.open = string(r)
+		}
+	case r == '[' && m.open == "":
+		m.link = 1
+	}
+
+	return i + size
+}
+
+func (m *markdownState) balanced() bool {
+	return m.open == "" && m.link == 0
+}
+
+func (m *markdownState) atomic() bool {
+	return m.link != 0
+}
+
+func (m *markdownState) closers() string {
+	if m.open == "```" {
+		return "\n```"
+	}
+
+	return m.open
+}
+
+func (m *markdownState) openers() string {
+	if m.open == "```" {
+		return "```\n"
+	}
+
+	return m.open
+}
+
+// markdownV2State разбирает MarkdownV2 Telegram: вложенные *bold*, _italic_, __underline__,
+// ~strike~, ||spoiler||, а также `code`, ```pre``` и [text](url)
+type markdownV2State struct {
+	stack []string
+	code  string // "`" или "```", если разбирается код
+	link  int    // 0 — вне ссылки, 1 — текст ссылки, 2 — после "]", 3 — url
+}
+
+func (m *markdownV2State) next(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+
+	// Экранирование действует везде, включая код и ссылки
+	if r == '\\' && i+size < len(text) {
+		_, escaped := utf8.DecodeRuneInString(text[i+size:])
+		return i + size + escaped
+	}
+
+	if m.code != "" {
+		if strings.HasPrefix(text[i:], m.code) {
+			n := len(m.code)
+			m.code = ""
+			return i + n
+		}
+		return i + size
+	}
+
+	switch m.link {
+	case 1:
+		if r == ']' {
+			m.link = 2
+		}
+		return i + size
+	case 2:
+		if r == '(' {
+			m.link = 3
+			return i + size
+		}
+		m.link = 0
+	case 3:
+		if r == ')' {
+			m.link = 0
+		}
+		return i + size
+	}
+
+	var token string
+	switch {
+	case strings.HasPrefix(text[i:], "```"):
+		m.code = "```"
+		return i + 3
+	case r == '`':
+		m.code = "`"
+		return i + size
+	case r == '[':
+		m.link = 1
+		return i + size
+	case strings.HasPrefix(text[i:], "__"):
+		token = "__"
+	case strings.HasPrefix(text[i:], "||"):
+		token = "||"
+	case r == '*' || r == '_' || r == '~':
+		token = string(r)
+	default:
+		return i + size
+	}
+
+	for j := len(m.stack) - 1; j >= 0; j-- {
+		if m.stack[j] == token {
+			m.stack = m.stack[:j]
+			return i + len(token)
+		}
+	}
+	m.stack = append(m.stack, token)
+
+	return i + len(token)
+}
+
+func (m *markdownV2State) balanced() bool {
+	return len(m.stack) == 0 && m.code == "" && m.link == 0
+}
+
+func (m *markdownV2State) atomic() bool {
+	return m.link != 0
+}
+
+func (m *markdownV2State) closers() string {
+	var b strings.Builder
+	if m.code == "```" {
+		b.WriteString("\n```")
+	} else {
+		b.WriteString(m.code)
+	}
+	for j := len(m.stack) - 1; j >= 0; j-- {
+		b.WriteString(m.stack[j])
+	}
+
+	return b.String()
+}
+
+func (m *markdownV2State) openers() string {
+	var b strings.Builder
+	for _, token := range m.stack {
+		b.WriteString(token)
+	}
+	if m.code == "```" {
+		b.WriteString("```\n")
+	} else {
+		b.WriteString(m.code)
+	}
+
+	return b.String()
+}
+
+// htmlState разбирает HTML-разметку Telegram: теги и именованные/числовые сущности
+type htmlState struct {
+	stack []htmlTag
+}
+
+type htmlTag struct {
+	name string
+	open string
+}
+
+func (h *htmlState) next(text string, i int) int {
+	switch text[i] {
+	case '<':
+		end := strings.IndexByte(text[i:], '>')
+		if end < 0 {
+			break
+		}
+		tag := text[i : i+end+1]
+		h.handleTag(tag)
+		return i + end + 1
+	case '&':
+		end := strings.IndexByte(text[i:], ';')
+		if end > 0 && end <= 10 {
+			return i + end + 1
+		}
+	}
+
+	_, size := utf8.DecodeRuneInString(text[i:])
+	return i + size
+}
+
+func (h *htmlState) handleTag(tag string) {
+	inner := strings.Trim(tag, "<>")
+	if strings.HasPrefix(inner, "/") {
+		name := strings.ToLower(strings.TrimSpace(inner[1:]))
+		for j := len(h.stack) - 1; j >= 0; j-- {
+			if h.stack[j].name == name {
+				h.stack = h.stack[:j]
+				return
+			}
+		}
+		return
+	}
+	if strings.HasSuffix(inner, "/") {
+		return
+	}
+
+	name := inner
+	if k := strings.IndexAny(inner, " \t\n"); k >= 0 {
+		name = inner[:k]
+	}
+	h.stack = append(h.stack, htmlTag{name: strings.ToLower(name), open: tag})
+}
+
+func (h *htmlState) balanced() bool {
+	return len(h.stack) == 0
+}
+
+func (h *htmlState) atomic() bool {
+	return false
+}
+
+func (h *htmlState) closers() string {
+	var b strings.Builder
+	for j := len(h.stack) - 1; j >= 0; j-- {
+		b.WriteString("</" + h.stack[j].name + ">")
+	}
+
+	return b.String()
+}
+
+func (h *htmlState) openers() string {
+	var b strings.Builder
+	for _, tag := range h.stack {
+		b.WriteString(tag.open)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		mode model.ParseMode
+		text string
+		want string
+	}{
+		{name: "plain", mode: model.ParseModePlain, text: "*a_b* <c>", want: "*a_b* <c>"},
+		{name: "markdown", mode: model.ParseModeMarkdown, text: "*a_b* `c` [d]", want: "\\*a\\_b\\* \\`c\\` \\[d]"},
+		{name: "markdown v2", mode: model.ParseModeMarkdownV2, text: "a.b-c (d)!", want: "a\\.b\\-c \\(d\\)\\!"},
+		{name: "markdown v2 backslash", mode: model.ParseModeMarkdownV2, text: `a\b`, want: `a\\b`},
+		{name: "html", mode: model.ParseModeHTML, text: `<b>&"`, want: "&lt;b&gt;&amp;&#34;"},
+		{name: "unknown mode as plain", mode: "bbcode", text: "*a*", want: "*a*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markupFor(tt.mode).escape(tt.text); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkupState(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         model.ParseMode
+		text         string
+		wantBalanced bool
+		wantAtomic   bool
+		wantClosers  string
+		wantOpeners  string
+	}{
+		{name: "plain", mode: model.ParseModePlain, text: "*a _b", wantBalanced: true},
+
+		{name: "markdown closed bold", mode: model.ParseModeMarkdown, text: "*a* b", wantBalanced: true},
+		{name: "markdown open bold", mode: model.ParseModeMarkdown, text: "*a b", wantClosers: "*", wantOpeners: "*"},
+		{name: "markdown other marker inside bold", mode: model.ParseModeMarkdown, text: "*a _b", wantClosers: "*", wantOpeners: "*"},
+		{name: "markdown escaped marker", mode: model.ParseModeMarkdown, text: "\\*a", wantBalanced: true},
+		{name: "markdown code ignores markers", mode: model.ParseModeMarkdown, text: "`*a` b", wantBalanced: true},
+		{name: "markdown open pre", mode: model.ParseModeMarkdown, text: "```\na", wantClosers: "\n```", wantOpeners: "```\n"},
+		{name: "markdown link text", mode: model.ParseModeMarkdown, text: "[a b", wantAtomic: true},
+		{name: "markdown link url", mode: model.ParseModeMarkdown, text: "[a](http://x", wantAtomic: true},
+		{name: "markdown closed link", mode: model.ParseModeMarkdown, text: "[a](http://x) b", wantBalanced: true},
+
+		{name: "markdown v2 nested open", mode: model.ParseModeMarkdownV2, text: "*a _b", wantClosers: "_*", wantOpeners: "*_"},
+		{name: "markdown v2 nested closed", mode: model.ParseModeMarkdownV2, text: "*a _b_ c*", wantBalanced: true},
+		{name: "markdown v2 inner closed", mode: model.ParseModeMarkdownV2, text: "*a _b_ c", wantClosers: "*", wantOpeners: "*"},
+		{name: "markdown v2 underline and spoiler", mode: model.ParseModeMarkdownV2, text: "__a ||b", wantClosers: "||__", wantOpeners: "__||"},
+		{name: "markdown v2 strike inside italic", mode: model.ParseModeMarkdownV2, text: "_a ~b", wantClosers: "~_", wantOpeners: "_~"},
+		{name: "markdown v2 escaped marker", mode: model.ParseModeMarkdownV2, text: "\\*a\\_", wantBalanced: true},
+		{name: "markdown v2 code ignores markers", mode: model.ParseModeMarkdownV2, text: "`*a _b` c", wantBalanced: true},
+		{name: "markdown v2 escape inside code", mode: model.ParseModeMarkdownV2, text: "`a\\`", wantClosers: "`", wantOpeners: "`"},
+		{name: "markdown v2 pre inside bold", mode: model.ParseModeMarkdownV2, text: "*a ```\nb", wantClosers: "\n```*", wantOpeners: "*```\n"},
+		{name: "markdown v2 link inside bold", mode: model.ParseModeMarkdownV2, text: "*[a", wantAtomic: true, wantClosers: "*", wantOpeners: "*"},
+
+		{name: "html nested open", mode: model.ParseModeHTML, text: "<b>a <i>b", wantClosers: "</i></b>", wantOpeners: "<b><i>"},
+		{name: "html closed", mode: model.ParseModeHTML, text: "<b>a <i>b</i></b>", wantBalanced: true},
+		{name: "html attributes kept in openers", mode: model.ParseModeHTML, text: `<a href="http://x">a`, wantClosers: "</a>", wantOpeners: `<a href="http://x">`},
+		{name: "html tag names case insensitive", mode: model.ParseModeHTML, text: "<B>a</b>", wantBalanced: true},
+		{name: "html self-closing tag", mode: model.ParseModeHTML, text: "a<br/>b", wantBalanced: true},
+		{name: "html entity", mode: model.ParseModeHTML, text: "a &amp; b", wantBalanced: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := markupFor(tt.mode).newState()
+			for i := 0; i < len(tt.text); {
+				i = state.next(tt.text, i)
+			}
+
+			if got := state.balanced(); got != tt.wantBalanced {
+				t.Errorf("balanced() = %v, want %v", got, tt.wantBalanced)
+			}
+			if got := state.atomic(); got != tt.wantAtomic {
+				t.Errorf("atomic() = %v, want %v", got, tt.wantAtomic)
+			}
+			if got := state.closers(); got != tt.wantClosers {
+				t.Errorf("closers() = %q, want %q", got, tt.wantClosers)
+			}
+			if got := state.openers(); got != tt.wantOpeners {
+				t.Errorf("openers() = %q, want %q", got, tt.wantOpeners)
+			}
+		})
+	}
+}
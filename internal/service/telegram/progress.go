@@ -0,0 +1,98 @@
+package telegram
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+// progressTTL — сколько хранится прогресс доставки события, которое не удалось доставить во все чаты
+const progressTTL = time.Hour
+
+// chatState — результат доставки события в чат
+type chatState int
+
+const (
+	chatPending chatState = iota
+	chatDelivered
+	// chatRejected — Telegram окончательно отклонил сообщение, повторять отправку в чат бессмысленно
+	chatRejected
+)
+
+// chatProgress — состояние доставки события в чат
+type chatProgress struct {
+	state chatState
+	// parts — message_id уже отправленных частей длинного сообщения
+	parts []int
+}
+
+// eventProgress — состояние доставки события по чатам маршрута
+type eventProgress struct {
+	chats     map[int64]chatProgress
+	updatedAt time.Time
+}
+
+// progress запоминает чаты, в которые событие уже доставлено, и отправленные части длинного
+// сообщения, чтобы повторная обработка после частичной ошибки продолжала отправку с места сбоя.
+// Прогресс хранится в памяти: повторы retry middleware выполняются в том же процессе
+type progress struct {
+	mu     sync.Mutex
+	events map[string]*eventProgress
+}
+
+// begin возвращает прогресс доставки события, создавая его при первой попытке
+func (p *progress) begin(key string, now time.Time) *eventProgress {
+	if key == "" {
+		return &eventProgress{chats: make(map[int64]chatProgress)}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for k, event := range p.events {
+		if now.Sub(event.updatedAt) > progressTTL {
+			delete(p.events, k)
+		}
+	}
+	if p.events == nil {
+		p.events = make(map[string]*eventProgress)
+	}
+
+	event, ok := p.events[key]
+	if !ok {
+		event = &eventProgress{chats: make(map[int64]chatProgress)}
+		p.events[key] = event
+	}
+	event.updatedAt = now
+
+	return event
+}
+
+// finish забывает прогресс события, обработка которого завершена
+func (p *progress) finish(key string) {
+	p.mu.Lock()
+	delete(p.events, key)
+	p.mu.Unlock()
+}
+
+// delivered сообщает, что событие доставлено хотя бы в один чат
+func (e *eventProgress) delivered() bool {
+	for _, chat := range e.chats {
+		if chat.state == chatDelivered {
+			return true
+		}
+	}
+
+	return false
+}
+
+// progressKey идентифицирует событие по позиции kafka-сообщения
+func progressKey(event model.AssembledEvent) string {
+	if event.Kafka.Topic == "" {
+		return event.EventUuid
+	}
+
+	return fmt.Sprintf("%s/%d/%d", event.Kafka.Topic, event.Kafka.Partition, event.Kafka.Offset)
+}
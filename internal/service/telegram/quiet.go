@@ -0,0 +1,446 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/major1ink/simple-notification-telegram/internal/client/http"
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
+)
+
+// maxHeldMessages — сколько отложенных сообщений попадает в сводку чата, остальные только подсчитываются
+const maxHeldMessages = 50
+
+// Задержки повторной отправки отложенных уведомлений после временной ошибки
+const (
+	heldRetryInitial = time.Minute
+	heldRetryMax     = 15 * time.Minute
+)
+
+// HeldStore хранит уведомления, отложенные до окончания тихих часов
+type HeldStore interface {
+	Add(notification model.HeldNotification) (uint64, error)
+	List() ([]model.HeldNotification, error)
+	Save(notification model.HeldNotification) error
+	Delete(id uint64) error
+}
+
+// holder планирует отправку отложенных уведомлений по имени правила маршрутизации.
+// Сами уведомления лежат в HeldStore и переживают перезапуск
+type holder struct {
+	store HeldStore
+
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	due     map[string]time.Time
+	backoff map[string]time.Duration
+	closed  bool
+
+	// flushMu не даёт отправлять отложенные уведомления параллельно
+	flushMu sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// heldChat — чат и тема, в которые отправляется сводка
+type heldChat struct {
+	chatID   int64
+	threadID int
+}
+
+// SetHeldStore включает откладывание уведомлений на тихие часы и планирует отправку
+// уведомлений, сохранённых до перезапуска
+func (s *service) SetHeldStore(store HeldStore) error {
+	s.held.store = store
+	s.held.timers = make(map[string]*time.Timer)
+	s.held.due = make(map[string]time.Time)
+	s.held.backoff = make(map[string]time.Duration)
+	s.held.ctx, s.held.cancel = context.WithCancel(context.Background())
+
+	notifications, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to load held notifications: %w", err)
+	}
+
+	// Уведомления, тихие часы которых закончились во время простоя, отправляются сразу
+	for _, notification := range notifications {
+		s.schedule(notification.Rule, notification.Until)
+	}
+	if len(notifications) > 0 {
+		s.logger.Info("Held notifications restored", zap.Int("count", len(notifications)))
+	}
+
+	return nil
+}
+
+// hold сохраняет сообщение до окончания тихих часов until. Возвращает false,
+// если сохранить не удалось и сообщение нужно отправить сразу
+func (s *service) hold(ruleName string, route router.Route, event model.AssembledEvent, message string, silent bool, until time.Time) bool {
+	if s.held.store == nil {
+		// Хранилище открывается при запуске, только если какой-то маршрут откладывает уведомления
+		s.logger.Warn("Held notifications store is not opened, restart is required to hold events; sending silently",
+			zap.String("rule", ruleName),
+			zap.String("event_uuid", event.EventUuid),
+		)
+		return false
+	}
+
+	notification := model.HeldNotification{
+		Rule:      ruleName,
+		ChatIDs:   route.ChatIDs,
+		ThreadID:  route.ThreadID,
+		Event:     event,
+		Kafka:     event.Kafka,
+		EventTime: event.Time,
+		Message:   message,
+		Silent:    silent,
+		Until:     until,
+	}
+	if s.correlation.Store != nil {
+		notification.CorrelationKey = s.correlation.Key(event)
+	}
+
+	if _, err := s.held.store.Add(notification); err != nil {
+		s.logger.Error("Failed to hold event until quiet hours end, sending silently",
+			zap.String("rule", ruleName),
+			zap.String("event_uuid", event.EventUuid),
+			zap.Error(err),
+		)
+		return false
+	}
+	s.schedule(ruleName, until)
+
+	s.logger.Debug("Event held until quiet hours end",
+		zap.String("rule", ruleName),
+		zap.String("event_uuid", event.EventUuid),
+		zap.Time("until", until),
+	)
+
+	return true
+}
+
+// schedule планирует отправку уведомлений правила на момент at, если она не запланирована раньше
+func (s *service) schedule(ruleName string, at time.Time) {
+	s.held.mu.Lock()
+	defer s.held.mu.Unlock()
+
+	if s.held.closed {
+		return
+	}
+	if due, ok := s.held.due[ruleName]; ok && !at.Before(due) {
+		return
+	}
+
+	if timer, ok := s.held.timers[ruleName]; ok {
+		timer.Stop()
+	}
+	s.held.due[ruleName] = at
+	s.held.timers[ruleName] = time.AfterFunc(time.Until(at), func() { s.flush(ruleName) })
+}
+
+// flush отправляет уведомления правила, тихие часы которых закончились.
+// Уведомления, которые не удалось отправить, остаются в хранилище и отправляются повторно
+func (s *service) flush(ruleName string) {
+	s.held.mu.Lock()
+	if s.held.closed {
+		s.held.mu.Unlock()
+		return
+	}
+	delete(s.held.timers, ruleName)
+	delete(s.held.due, ruleName)
+	s.held.wg.Add(1)
+	s.held.mu.Unlock()
+	defer s.held.wg.Done()
+
+	s.held.flushMu.Lock()
+	defer s.held.flushMu.Unlock()
+
+	notifications, err := s.held.store.List()
+	if err != nil {
+		s.logger.Error("Failed to load held notifications",
+			zap.String("rule", ruleName),
+			zap.Error(err),
+		)
+		s.retry(ruleName)
+		return
+	}
+
+	now := time.Now()
+	var due []model.HeldNotification
+	for _, notification := range notifications {
+		if notification.Rule != ruleName {
+			continue
+		}
+		if notification.Until.After(now) {
+			s.schedule(ruleName, notification.Until)
+			continue
+		}
+		due = append(due, notification)
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	if !s.sendHeld(s.held.ctx, ruleName, due) {
+		s.retry(ruleName)
+		return
+	}
+
+	s.held.mu.Lock()
+	delete(s.held.backoff, ruleName)
+	s.held.mu.Unlock()
+}
+
+// retry планирует повторную отправку уведомлений правила с растущей задержкой
+func (s *service) retry(ruleName string) {
+	s.held.mu.Lock()
+	delay := min(2*s.held.backoff[ruleName], heldRetryMax)
+	if delay == 0 {
+		delay = heldRetryInitial
+	}
+	s.held.backoff[ruleName] = delay
+	s.held.mu.Unlock()
+
+	s.logger.Warn("Held notifications will be resent",
+		zap.String("rule", ruleName),
+		zap.Duration("backoff", delay),
+	)
+	s.schedule(ruleName, time.Now().Add(delay))
+}
+
+// Close останавливает отправку отложенных уведомлений и ждёт завершения начатой.
+// Неотправленные уведомления остаются в хранилище и отправляются после перезапуска
+func (s *service) Close(ctx context.Context) error {
+	s.held.mu.Lock()
+	s.held.closed = true
+	for _, timer := range s.held.timers {
+		timer.Stop()
+	}
+	if s.held.cancel != nil {
+		s.held.cancel()
+	}
+	s.held.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.held.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sendHeld отправляет отложенные уведомления в чаты, где они ещё не доставлены, и сохраняет,
+// куда доставить не удалось. Возвращает false, если часть уведомлений нужно отправить повторно
+func (s *service) sendHeld(ctx context.Context, ruleName string, due []model.HeldNotification) bool {
+	var chats []heldChat
+	pending := make(map[heldChat][]int)
+	for i, notification := range due {
+		for _, chatID := range notification.ChatIDs {
+			chat := heldChat{chatID: chatID, threadID: notification.ThreadID}
+			if _, ok := pending[chat]; !ok {
+				chats = append(chats, chat)
+			}
+			pending[chat] = append(pending[chat], i)
+		}
+	}
+
+	complete := true
+	done := make([]map[int64]bool, len(due))
+	for _, chat := range chats {
+		finished, ok := s.sendHeldToChat(ctx, ruleName, chat, due, pending[chat])
+		for _, i := range finished {
+			if done[i] == nil {
+				done[i] = make(map[int64]bool)
+			}
+			done[i][chat.chatID] = true
+		}
+		complete = complete && ok
+	}
+
+	for i, notification := range due {
+		var chatIDs []int64
+		for _, chatID := range notification.ChatIDs {
+			if !done[i][chatID] {
+				chatIDs = append(chatIDs, chatID)
+			}
+		}
+
+		var err error
+		switch {
+		case len(chatIDs) == 0:
+			err = s.held.store.Delete(notification.ID)
+		case len(chatIDs) < len(notification.ChatIDs):
+			notification.ChatIDs = chatIDs
+			err = s.held.store.Save(notification)
+		}
+		if err != nil {
+			s.logger.Error("Failed to update held notification",
+				zap.String("rule", ruleName),
+				zap.String("event_uuid", notification.Event.EventUuid),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return complete
+}
+
+// sendHeldToChat отправляет отложенные уведомления в чат. Продолжения инцидентов, сообщение
+// о которых уже есть в чате, обновляют его, остальные уведомления объединяются в сводку.
+// Возвращает уведомления, которые больше не нужно отправлять в этот чат, и false,
+// если часть из них не отправлена из-за временной ошибки
+func (s *service) sendHeldToChat(ctx context.Context, ruleName string, chat heldChat, due []model.HeldNotification, pending []int) ([]int, bool) {
+	var (
+		finished []int
+		digest   []int
+		complete = true
+	)
+	for _, i := range pending {
+		notification := due[i]
+		previous := s.previous(notification.CorrelationKey)
+		if _, ok := findMessage(previous, chat.chatID); !ok {
+			digest = append(digest, i)
+			continue
+		}
+
+		event := heldEvent(notification)
+		started := time.Now()
+		messageIDs, original, err := s.deliver(ctx, chat.chatID, chat.threadID, event, notification.Message, notification.Silent, previous, nil)
+		s.record(event, chat.chatID, chat.threadID, messageIDs, started, err)
+		if original != nil {
+			s.remember(notification.CorrelationKey, previous, []model.SentMessage{*original})
+		}
+		if err != nil {
+			s.logger.Error("Failed to send held telegram message",
+				zap.String("rule", ruleName),
+				zap.Int64("chat_id", chat.chatID),
+				zap.String("event_uuid", event.EventUuid),
+				zap.Error(err),
+			)
+			if !errors.Is(err, http.ErrRejected) {
+				complete = false
+				continue
+			}
+		}
+		finished = append(finished, i)
+	}
+	if len(digest) == 0 {
+		return finished, complete
+	}
+
+	message := s.heldDigest(due, digest)
+	silent := true
+	for _, i := range digest {
+		silent = silent && due[i].Silent
+	}
+
+	// Повтор после временной ошибки продолжает отправку длинной сводки со следующей части.
+	// Ключ включает идентификаторы уведомлений: если состав сводки изменился, она отправляется заново
+	progressID := heldProgressKey(ruleName, chat, due, digest)
+	state := s.progress.begin(progressID, time.Now())
+
+	started := time.Now()
+	messageIDs, err := s.send(ctx, chat.chatID, chat.threadID, model.AssembledEvent{}, message, silent, state.chats[chat.chatID].parts)
+	for _, i := range digest {
+		s.record(heldEvent(due[i]), chat.chatID, chat.threadID, messageIDs, started, err)
+	}
+	if err != nil {
+		s.logger.Error("Failed to send held telegram messages",
+			zap.String("rule", ruleName),
+			zap.Int64("chat_id", chat.chatID),
+			zap.Int("count", len(digest)),
+			zap.Error(err),
+		)
+		if !errors.Is(err, http.ErrRejected) {
+			state.chats[chat.chatID] = chatProgress{parts: messageIDs}
+			return finished, false
+		}
+		s.progress.finish(progressID)
+		return append(finished, digest...), complete
+	}
+	s.progress.finish(progressID)
+
+	// Следующие события инцидентов из сводки отвечают на неё
+	sent := model.SentMessage{
+		ChatID:    chat.chatID,
+		ThreadID:  chat.threadID,
+		MessageID: messageIDs[0],
+		Document:  s.asDocument(message),
+		Digest:    true,
+	}
+	for _, i := range digest {
+		if key := due[i].CorrelationKey; key != "" {
+			s.remember(key, s.previous(key), []model.SentMessage{sent})
+		}
+	}
+
+	s.logger.Info("Held telegram messages sent to chat",
+		zap.String("rule", ruleName),
+		zap.Int64("chat_id", chat.chatID),
+		zap.Int("count", len(digest)),
+	)
+
+	return append(finished, digest...), complete
+}
+
+// heldDigest объединяет отложенные сообщения в одну сводку
+func (s *service) heldDigest(due []model.HeldNotification, indexes []int) string {
+	mk := markupFor(s.cfg.ParseMode)
+	shown := indexes[:min(len(indexes), maxHeldMessages)]
+
+	parts := make([]string, 0, len(shown)+2)
+	parts = append(parts, mk.escape(fmt.Sprintf("🌙 Уведомления за тихие часы: %d", len(indexes))))
+	for _, i := range shown {
+		parts = append(parts, due[i].Message)
+	}
+	if skipped := len(indexes) - len(shown); skipped > 0 {
+		parts = append(parts, mk.escape(fmt.Sprintf("…и ещё %d", skipped)))
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// heldProgressKey идентифицирует сводку по чату и отложенным уведомлениям, из которых она собрана
+func heldProgressKey(ruleName string, chat heldChat, due []model.HeldNotification, indexes []int) string {
+	ids := make([]uint64, 0, len(indexes))
+	for _, i := range indexes {
+		ids = append(ids, due[i].ID)
+	}
+	slices.Sort(ids)
+
+	var key strings.Builder
+	fmt.Fprintf(&key, "held/%s/%d/%d/", ruleName, chat.chatID, chat.threadID)
+	for i, id := range ids {
+		if i > 0 {
+			key.WriteByte(',')
+		}
+		key.WriteString(strconv.FormatUint(id, 10))
+	}
+
+	return key.String()
+}
+
+// heldEvent восстанавливает событие отложенного уведомления
+func heldEvent(notification model.HeldNotification) model.AssembledEvent {
+	event := notification.Event
+	event.Kafka = notification.Kafka
+	event.Time = notification.EventTime
+
+	return event
+}
@@ -0,0 +1,304 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/pkg/kafka/consumer"
+)
+
+const (
+	defaultTemplateName = "default"
+	statusTemplateName  = "status"
+)
+
+// TemplateRule — выбор шаблона по привязке topic, типу события и/или сервису.
+// Пустое поле совпадает с любым значением.
+type TemplateRule struct {
+	Binding   string
+	App       string
+	TypeEvent string
+	Template  string
+}
+
+// TemplateConfig — параметры загрузки шаблонов сообщений
+type TemplateConfig struct {
+	Dir       string          // Директория с *.tmpl файлами
+	Default   string          // Шаблон по умолчанию из Dir (если пусто — встроенный)
+	Status    string          // Шаблон строки статуса для обновлений инцидента из Dir (если пусто — встроенный)
+	Rules     []TemplateRule  // Правила выбора шаблона, проверяются по порядку
+	ParseMode model.ParseMode // Режим разметки, под который экранируются поля
+	// SeverityPrefixes — префиксы уровней важности для функции severityPrefix, дополняют DefaultSeverityPrefixes
+	SeverityPrefixes map[model.Severity]string
+}
+
+// DefaultSeverityPrefixes возвращает префиксы уровней важности по умолчанию
+func DefaultSeverityPrefixes() map[model.Severity]string {
+	return map[model.Severity]string{
+		model.SeverityDebug:    "🐞",
+		model.SeverityInfo:     "ℹ️",
+		model.SeverityWarning:  "⚠️",
+		model.SeverityError:    "❌",
+		model.SeverityCritical: "🔥",
+	}
+}
+
+type templateSet struct {
+	templates map[string]*template.Template
+	rules     []TemplateRule
+	def       *template.Template
+	status    *template.Template
+}
+
+// Renderer формирует текст уведомления по шаблону, выбранному для события
+type Renderer struct {
+	mu  sync.RWMutex
+	set templateSet
+}
+
+// NewRenderer загружает шаблоны. Ошибки разбора шаблонов возвращаются сразу,
+// чтобы они проявлялись при старте, а не при отправке.
+func NewRenderer(cfg TemplateConfig) (*Renderer, error) {
+	r := &Renderer{}
+	if err := r.Update(cfg); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Update перечитывает шаблоны и атомарно заменяет текущий набор
+func (r *Renderer) Update(cfg TemplateConfig) error {
+	set, err := loadTemplateSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.set = set
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Render формирует текст сообщения для события. Ошибка выполнения шаблона неустранима
+func (r *Renderer) Render(event model.AssembledEvent) (string, error) {
+	r.mu.RLock()
+	set := r.set
+	r.mu.RUnlock()
+
+	tmpl := set.lookup(event)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData(event)); err != nil {
+		// Шаблон выполнится с той же ошибкой и при повторе
+		return "", consumer.Permanent(fmt.Errorf("failed to execute template %s: %w", tmpl.Name(), err))
+	}
+
+	return buf.String(), nil
+}
+
+// RenderStatus формирует строку статуса, добавляемую к обновлению инцидента
+func (r *Renderer) RenderStatus(event model.AssembledEvent) (string, error) {
+	r.mu.RLock()
+	tmpl := r.set.status
+	r.mu.RUnlock()
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData(event)); err != nil {
+		// Шаблон выполнится с той же ошибкой и при повторе
+		return "", consumer.Permanent(fmt.Errorf("failed to execute template %s: %w", tmpl.Name(), err))
+	}
+
+	return buf.String(), nil
+}
+
+func (s templateSet) lookup(event model.AssembledEvent) *template.Template {
+	for _, rule := range s.rules {
+		if rule.Binding != "" && rule.Binding != event.Kafka.Binding {
+			continue
+		}
+		if rule.App != "" && rule.App != event.App {
+			continue
+		}
+		if rule.TypeEvent != "" && rule.TypeEvent != event.TypeEvent {
+			continue
+		}
+
+		return s.templates[rule.Template]
+	}
+
+	return s.def
+}
+
+func loadTemplateSet(cfg TemplateConfig) (templateSet, error) {
+	set := templateSet{
+		templates: make(map[string]*template.Template),
+		rules:     cfg.Rules,
+	}
+
+	prefixes := DefaultSeverityPrefixes()
+	for severity, prefix := range cfg.SeverityPrefixes {
+		prefixes[severity] = prefix
+	}
+	funcs := templateFuncs(markupFor(cfg.ParseMode), prefixes)
+
+	def, err := parseTemplate(defaultTemplateName, embeddedTemplateSource(cfg.ParseMode), funcs)
+	if err != nil {
+		return templateSet{}, fmt.Errorf("failed to parse embedded template: %w", err)
+	}
+	set.def = def
+
+	status, err := parseTemplate(statusTemplateName, statusTemplateSource, funcs)
+	if err != nil {
+		return templateSet{}, fmt.Errorf("failed to parse embedded status template: %w", err)
+	}
+	set.status = status
+
+	if cfg.Dir != "" {
+		err = filepath.WalkDir(cfg.Dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || filepath.Ext(path) != ".tmpl" {
+				return nil
+			}
+
+			name, err := filepath.Rel(cfg.Dir, path)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(name)
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			tmpl, err := parseTemplate(name, string(content), funcs)
+			if err != nil {
+				return fmt.Errorf("failed to parse template %s: %w", name, err)
+			}
+			set.templates[name] = tmpl
+
+			return nil
+		})
+		if err != nil {
+			return templateSet{}, fmt.Errorf("failed to load templates from %s: %w", cfg.Dir, err)
+		}
+	}
+
+	if cfg.Default != "" {
+		tmpl, ok := set.templates[cfg.Default]
+		if !ok {
+			return templateSet{}, fmt.Errorf("default template %s not found in %s", cfg.Default, cfg.Dir)
+		}
+		set.def = tmpl
+	}
+
+	if cfg.Status != "" {
+		tmpl, ok := set.templates[cfg.Status]
+		if !ok {
+			return templateSet{}, fmt.Errorf("status template %s not found in %s", cfg.Status, cfg.Dir)
+		}
+		set.status = tmpl
+	}
+
+	for _, rule := range cfg.Rules {
+		if _, ok := set.templates[rule.Template]; !ok {
+			return templateSet{}, fmt.Errorf("template %s for binding=%q app=%q type_event=%q not found in %s",
+				rule.Template, rule.Binding, rule.App, rule.TypeEvent, cfg.Dir)
+		}
+	}
+
+	return set, nil
+}
+
+func parseTemplate(name, content string, funcs template.FuncMap) (*template.Template, error) {
+	return template.New(name).Funcs(funcs).Parse(content)
+}
+
+// templateFuncs возвращает функции шаблонов. Функции экранирования учитывают режим разметки
+func templateFuncs(mk markup, prefixes map[model.Severity]string) template.FuncMap {
+	escapeMarkdown := escapeMarkdownLegacy
+	if mk.parseMode == model.ParseModeMarkdownV2 {
+		escapeMarkdown = escapeMarkdownV2
+	}
+
+	return template.FuncMap{
+		"escape":         mk.escape,
+		"escapeMarkdown": escapeMarkdown,
+		"escapeHTML":     html.EscapeString,
+		"truncate":       truncate,
+		"formatTime":     formatTime,
+		"upper":          strings.ToUpper,
+		"lower":          strings.ToLower,
+		"default":        defaultValue,
+		"severityPrefix": func(severity string) string {
+			level, _ := model.ParseSeverity(severity)
+			return prefixes[level]
+		},
+	}
+}
+
+// truncate обрезает строку до n символов, добавляя многоточие
+func truncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	if n == 1 {
+		return "…"
+	}
+
+	return string(runes[:n-1]) + "…"
+}
+
+// formatTime форматирует время по layout. Принимает time.Time или строку в RFC3339
+func formatTime(layout string, value any) string {
+	switch t := value.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return t
+		}
+		return parsed.Format(layout)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// defaultValue возвращает def, если значение пустое
+func defaultValue(def any, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return def
+	case string:
+		if v == "" {
+			return def
+		}
+	}
+
+	return value
+}
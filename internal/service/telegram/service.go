@@ -1,73 +1,349 @@
 package telegram
 
 import (
-	"bytes"
 	"context"
-	"embed"
-	"text/template"
+	_ "embed"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
 
 	"go.uber.org/zap"
 
 	"github.com/major1ink/simple-notification-telegram/internal/client/http"
 	"github.com/major1ink/simple-notification-telegram/internal/model"
+	"github.com/major1ink/simple-notification-telegram/internal/router"
 )
 
-//go:embed templates/assembled_notification.tmpl
-var templateFS embed.FS
+var (
+	//go:embed templates/assembled_notification.tmpl
+	markdownTemplateSource string
+	//go:embed templates/assembled_notification.html.tmpl
+	htmlTemplateSource string
+	//go:embed templates/assembled_notification.txt.tmpl
+	plainTemplateSource string
+	//go:embed templates/status.tmpl
+	statusTemplateSource string
+)
+
+// embeddedTemplateSource возвращает встроенный шаблон для режима разметки
+func embeddedTemplateSource(mode model.ParseMode) string {
+	switch mode {
+	case model.ParseModeMarkdown, model.ParseModeMarkdownV2:
+		return markdownTemplateSource
+	case model.ParseModeHTML:
+		return htmlTemplateSource
+	default:
+		return plainTemplateSource
+	}
+}
 
 type assembledTemplateData struct {
 	EventUuid string
 	TypeEvent string
 	App       string
 	Message   string
+	Severity  string // Уровень важности или пустая строка
+	Fields    map[string]any
+	Raw       any
+
+	Topic     string
+	Key       string
+	Headers   map[string]string
+	Timestamp time.Time
+	// Time — время события из источника, если известно, иначе время kafka-сообщения
+	Time time.Time
 }
 
-var (
-	assembledTemplate = template.Must(template.ParseFS(templateFS, "templates/assembled_notification.tmpl"))
+func newTemplateData(assembledEvent model.AssembledEvent) assembledTemplateData {
+	eventTime := assembledEvent.Time
+	if eventTime.IsZero() {
+		eventTime = assembledEvent.Kafka.Timestamp
+	}
+
+	return assembledTemplateData{
+		EventUuid: assembledEvent.EventUuid,
+		TypeEvent: assembledEvent.TypeEvent,
+		App:       assembledEvent.App,
+		Message:   assembledEvent.Message,
+		Severity:  assembledEvent.Severity.String(),
+		Fields:    assembledEvent.Fields,
+		Raw:       assembledEvent.Raw,
+		Topic:     assembledEvent.Kafka.Topic,
+		Key:       assembledEvent.Kafka.Key,
+		Headers:   assembledEvent.Kafka.Headers,
+		Timestamp: assembledEvent.Kafka.Timestamp,
+		Time:      eventTime,
+	}
+}
+
+// LongMessageMode — способ отправки сообщений длиннее лимита Telegram
+type LongMessageMode string
+
+const (
+	LongMessageSplit    LongMessageMode = "split"
+	LongMessageDocument LongMessageMode = "document"
 )
 
+// Config — параметры формирования сообщений
+type Config struct {
+	LongMessageMode LongMessageMode
+	ParseMode       model.ParseMode
+	// SilentBelow — события с уровнем важности ниже отправляются без звука
+	SilentBelow model.Severity
+}
+
+// Journal — журнал попыток доставки уведомлений
+type Journal interface {
+	Record(delivery model.Delivery) error
+}
+
 type service struct {
 	telegramClient http.TelegramClient
 	logger         *zap.Logger
-	chatID         int64
+	router         *router.Router
+	renderer       *Renderer
+	journal        Journal
+	correlation    Correlation
+	held           holder
+	progress       progress
+	cfg            Config
 }
 
-func NewService(telegramClient http.TelegramClient, logger *zap.Logger, chatID int64) *service {
+// NewService создаёт сервис отправки уведомлений. journal может быть nil — журнал не ведётся
+func NewService(
+	telegramClient http.TelegramClient,
+	logger *zap.Logger,
+	router *router.Router,
+	renderer *Renderer,
+	journal Journal,
+	cfg Config,
+) *service {
 	return &service{
 		telegramClient: telegramClient,
 		logger:         logger,
-		chatID:         chatID,
+		router:         router,
+		renderer:       renderer,
+		journal:        journal,
+		cfg:            cfg,
 	}
 }
 
 func (s *service) SendAssembledNotification(ctx context.Context, assembledEvent model.AssembledEvent) error {
-	message, err := s.buildAssembledMessage(assembledEvent)
-	if err != nil {
-		return err
+	ruleName, route := s.router.Route(assembledEvent)
+	if route.Action == router.ActionDrop {
+		s.logger.Debug("Event dropped by routing rule",
+			zap.String("rule", ruleName),
+			zap.String("event_uuid", assembledEvent.EventUuid),
+		)
+		return nil
+	}
+	if !route.Allows(assembledEvent.Severity) {
+		s.logger.Debug("Event dropped by route severity filter",
+			zap.String("rule", ruleName),
+			zap.String("event_uuid", assembledEvent.EventUuid),
+			zap.Stringer("severity", assembledEvent.Severity),
+		)
+		return nil
 	}
 
-	err = s.telegramClient.SendMessage(ctx, s.chatID, message)
+	message, err := s.renderer.Render(assembledEvent)
 	if err != nil {
 		return err
 	}
 
-	s.logger.Debug("Telegram message sent to chat", zap.Int64("chat_id", s.chatID), zap.String("message", message))
+	silent := s.silent(assembledEvent)
+	if until, ok := route.QuietHours.Applies(assembledEvent.Severity, time.Now()); ok {
+		switch route.QuietHours.Action {
+		case router.QuietDrop:
+			s.logger.Debug("Event dropped by quiet hours",
+				zap.String("rule", ruleName),
+				zap.String("event_uuid", assembledEvent.EventUuid),
+			)
+			return nil
+		case router.QuietHold:
+			if s.hold(ruleName, route, assembledEvent, message, silent, until) {
+				return nil
+			}
+			silent = true
+		default:
+			silent = true
+		}
+	}
+
+	key, previous := s.incident(assembledEvent)
+
+	progressID := progressKey(assembledEvent)
+	state := s.progress.begin(progressID, time.Now())
+
+	var (
+		errs     []error
+		rejected []error
+		sent     []model.SentMessage
+	)
+	for _, chatID := range route.ChatIDs {
+		// Повторная обработка после частичной ошибки не отправляет сообщение в чаты, где оно уже есть
+		chat := state.chats[chatID]
+		if chat.state != chatPending {
+			continue
+		}
+
+		started := time.Now()
+		messageIDs, original, err := s.deliver(ctx, chatID, route.ThreadID, assembledEvent, message, silent, previous, chat.parts)
+		s.record(assembledEvent, chatID, route.ThreadID, messageIDs, started, err)
+		if original != nil {
+			sent = append(sent, *original)
+		}
+		if err != nil {
+			s.logger.Error("Failed to send telegram message",
+				zap.String("rule", ruleName),
+				zap.Int64("chat_id", chatID),
+				zap.Error(err),
+			)
+			if errors.Is(err, http.ErrRejected) {
+				state.chats[chatID] = chatProgress{state: chatRejected}
+				rejected = append(rejected, err)
+			} else {
+				// Повтор продолжит отправку длинного сообщения со следующей части
+				state.chats[chatID] = chatProgress{parts: messageIDs}
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		state.chats[chatID] = chatProgress{state: chatDelivered}
+		s.logger.Debug("Telegram message sent to chat",
+			zap.String("rule", ruleName),
+			zap.Int64("chat_id", chatID),
+			zap.Int("thread_id", route.ThreadID),
+			zap.String("message", message),
+		)
+	}
+
+	s.remember(key, previous, sent)
+
+	// Временные ошибки повторяются только для чатов, куда сообщение не доставлено
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	s.progress.finish(progressID)
+
+	// Отказ Telegram в части чатов не отправляет в DLQ событие, уже доставленное в другие чаты
+	if len(rejected) > 0 && !state.delivered() {
+		return errors.Join(rejected...)
+	}
+
 	return nil
 }
 
-func (s *service) buildAssembledMessage(assembledEvent model.AssembledEvent) (string, error) {
-	data := assembledTemplateData{
-		EventUuid: assembledEvent.EventUuid,
-		TypeEvent: assembledEvent.TypeEvent,
-		App:       assembledEvent.App,
-		Message:   assembledEvent.Message,
+// send отправляет сообщение в чат и возвращает message_id отправленных сообщений.
+// Сообщения длиннее лимита Telegram делятся на части или отправляются документом,
+// в зависимости от настроек. silent — отправить без звука. sent — message_id частей,
+// отправленных предыдущей попыткой: отправка продолжается со следующей части.
+// При ошибке возвращаются message_id частей, отправленных до неё.
+func (s *service) send(
+	ctx context.Context,
+	chatID int64,
+	threadID int,
+	assembledEvent model.AssembledEvent,
+	message string,
+	silent bool,
+	sent []int,
+) ([]int, error) {
+	mk := markupFor(s.cfg.ParseMode)
+
+	if s.asDocument(message) {
+		messageID, err := s.telegramClient.SendDocument(ctx, model.TelegramDocument{
+			ChatID:              chatID,
+			ThreadID:            threadID,
+			Filename:            documentFilename(assembledEvent),
+			Data:                []byte(message),
+			Caption:             headPart(message, maxCaptionLength, mk),
+			ParseMode:           mk.parseMode,
+			DisableNotification: silent,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []int{messageID}, nil
 	}
 
-	var buf bytes.Buffer
-	err := assembledTemplate.Execute(&buf, data)
+	parts := splitMessage(message, maxMessageLength, mk)
+	messageIDs := append([]int(nil), sent...)
+	for _, part := range parts[min(len(sent), len(parts)):] {
+		messageID, err := s.telegramClient.SendMessage(ctx, model.TelegramMessage{
+			ChatID:              chatID,
+			ThreadID:            threadID,
+			Text:                part,
+			ParseMode:           mk.parseMode,
+			DisableNotification: silent,
+		})
+		if err != nil {
+			return messageIDs, err
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	return messageIDs, nil
+}
+
+// silent сообщает, что событие низкой важности отправляется без звука
+func (s *service) silent(event model.AssembledEvent) bool {
+	return event.Severity != model.SeverityNone && event.Severity < s.cfg.SilentBelow
+}
+
+// asDocument сообщает, что сообщение отправляется документом
+func (s *service) asDocument(message string) bool {
+	return utf16Len(message) > maxMessageLength && s.cfg.LongMessageMode == LongMessageDocument
+}
+
+// record сохраняет попытку доставки в журнал. Ошибка журнала не влияет на доставку
+func (s *service) record(event model.AssembledEvent, chatID int64, threadID int, messageIDs []int, started time.Time, err error) {
+	if s.journal == nil {
+		return
+	}
+
+	delivery := model.Delivery{
+		EventUuid:  event.EventUuid,
+		Binding:    event.Kafka.Binding,
+		Topic:      event.Kafka.Topic,
+		Partition:  event.Kafka.Partition,
+		Offset:     event.Kafka.Offset,
+		ChatID:     chatID,
+		ThreadID:   threadID,
+		MessageIDs: messageIDs,
+		Status:     model.DeliverySent,
+		EventTime:  event.Kafka.Timestamp,
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+	}
+	if len(messageIDs) > 0 {
+		delivery.MessageID = messageIDs[0]
+	}
 	if err != nil {
-		return "", err
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = err.Error()
+	}
+
+	if err := s.journal.Record(delivery); err != nil {
+		s.logger.Warn("Failed to record delivery in journal",
+			zap.String("event_uuid", event.EventUuid),
+			zap.Int64("chat_id", chatID),
+			zap.Error(err),
+		)
+	}
+}
+
+func documentFilename(assembledEvent model.AssembledEvent) string {
+	name := assembledEvent.EventUuid
+	if name == "" {
+		name = "notification"
 	}
 
-	return buf.String(), nil
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name) + ".txt"
 }
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxMessageLength — максимальная длина сообщения Telegram в UTF-16 code units
+const maxMessageLength = 4096
+
+// maxCaptionLength — максимальная длина подписи к документу в UTF-16 code units
+const maxCaptionLength = 1024
+
+// partLabelReserve — запас под метку части "(NN/NN)" и закрывающие маркеры
+const partLabelReserve = 32
+
+// markupState отслеживает открытые сущности разметки при последовательном разборе текста
+type markupState interface {
+	// next разбирает токен, начинающийся с позиции i, и возвращает позицию следующего токена
+	next(text string, i int) int
+	// balanced сообщает, что в текущей позиции нет открытых сущностей
+	balanced() bool
+	// atomic сообщает, что текущая позиция внутри неделимой конструкции (например, ссылки)
+	atomic() bool
+	// closers возвращает маркеры, закрывающие открытые сущности
+	closers() string
+	// openers возвращает маркеры, повторно открывающие закрытые сущности в следующей части
+	openers() string
+}
+
+// utf16Len возвращает длину строки в UTF-16 code units, как её считает Telegram
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+
+	return n
+}
+
+// splitMessage делит текст на части не длиннее limit UTF-16 code units.
+// Разрез выполняется по границе строки, затем по пробелу, затем по границе
+// символа, и только в позиции без открытых сущностей разметки. Если такой
+// позиции нет, открытые сущности закрываются в конце части и открываются
+// заново в начале следующей. Части помечаются как "(1/3)".
+func splitMessage(text string, limit int, mk markup) []string {
+	if utf16Len(text) <= limit {
+		return []string{text}
+	}
+
+	budget := limit - partLabelReserve
+	var parts []string
+
+	rest := text
+	for rest != "" {
+		if utf16Len(rest) <= budget {
+			parts = append(parts, rest)
+			break
+		}
+
+		part, next := cutPart(rest, budget, mk.newState())
+		// Разрез не продвинулся: закрывающие и открывающие маркеры не оставили места под текст.
+		// Режем без учёта разметки, чтобы деление гарантированно завершилось
+		if len(next) >= len(rest) {
+			part, next = cutPart(rest, budget, plainState{})
+		}
+		parts = append(parts, part)
+		rest = next
+	}
+
+	for i := range parts {
+		label := mk.escape(fmt.Sprintf("(%d/%d)", i+1, len(parts)))
+		parts[i] = strings.TrimRight(parts[i], "\n") + "\n" + label
+	}
+
+	return parts
+}
+
+// headPart возвращает начало текста не длиннее limit, обрезанное по тем же правилам, что и в splitMessage
+func headPart(text string, limit int, mk markup) string {
+	if utf16Len(text) <= limit {
+		return text
+	}
+
+	part, _ := cutPart(text, limit-partLabelReserve, mk.newState())
+	return strings.TrimRight(part, "\n") + "\n…"
+}
+
+// cutPart отрезает от текста часть не длиннее budget и возвращает её и остаток
+func cutPart(text string, budget int, state markupState) (string, string) {
+	var (
+		safe   cutCandidates
+		unsafe cutCandidates
+		length int
+	)
+
+	for i := 0; i < len(text); {
+		j := state.next(text, i)
+		length += utf16Len(text[i:j])
+		if length > budget {
+			break
+		}
+
+		switch {
+		case state.balanced():
+			safe.add(text, j, "", "")
+		case !state.atomic():
+			closers := state.closers()
+			if length+utf16Len(closers) <= budget {
+				unsafe.add(text, j, closers, state.openers())
+			}
+		}
+
+		i = j
+	}
+
+	if c, ok := safe.best(); ok {
+		return text[:c.pos], text[c.pos:]
+	}
+	if c, ok := unsafe.best(); ok {
+		return text[:c.pos] + c.closers, c.openers + text[c.pos:]
+	}
+
+	// Неделимая конструкция длиннее лимита: режем по границе символа
+	cut, length := 0, 0
+	for i, r := range text {
+		length += utf16.RuneLen(r)
+		if length > budget {
+			break
+		}
+		cut = i + utf8.RuneLen(r)
+	}
+	if cut == 0 {
+		_, size := utf8.DecodeRuneInString(text)
+		cut = size
+	}
+
+	return text[:cut], text[cut:]
+}
+
+// cutPoint — позиция разреза с маркерами, закрывающими и открывающими сущности
+type cutPoint struct {
+	pos     int
+	closers string
+	openers string
+}
+
+// cutCandidates — последние допустимые позиции разреза по границе строки, пробелу и символу
+type cutCandidates struct {
+	line, space, any cutPoint
+}
+
+func (c *cutCandidates) add(text string, pos int, closers, openers string) {
+	p := cutPoint{pos: pos, closers: closers, openers: openers}
+	c.any = p
+	switch text[pos-1] {
+	case '\n':
+		c.line = p
+	case ' ':
+		c.space = p
+	}
+}
+
+func (c *cutCandidates) best() (cutPoint, bool) {
+	switch {
+	case c.line.pos > 0:
+		return c.line, true
+	case c.space.pos > 0:
+		return c.space, true
+	case c.any.pos > 0:
+		return c.any, true
+	}
+
+	return cutPoint{}, false
+}
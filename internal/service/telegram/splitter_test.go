@@ -0,0 +1,238 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/major1ink/simple-notification-telegram/internal/model"
+)
+
+func TestUTF16Len(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "ascii", text: "abc", want: 3},
+		{name: "cyrillic", text: "привет", want: 6},
+		{name: "surrogate pair", text: "😀", want: 2},
+		{name: "mixed", text: "a😀b", want: 4},
+		{name: "empty", text: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utf16Len(tt.text); got != tt.want {
+				t.Errorf("utf16Len(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCutPart(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     model.ParseMode
+		text     string
+		budget   int
+		wantPart string
+		wantRest string
+	}{
+		{
+			name: "cut at last space", mode: model.ParseModePlain,
+			text: "aaaa bbbb cccc", budget: 10,
+			wantPart: "aaaa bbbb ", wantRest: "cccc",
+		},
+		{
+			name: "line break preferred over space", mode: model.ParseModePlain,
+			text: "aa\nbb cc dd", budget: 10,
+			wantPart: "aa\n", wantRest: "bb cc dd",
+		},
+		{
+			name: "word longer than budget", mode: model.ParseModePlain,
+			text: "abcdefghijklmno", budget: 10,
+			wantPart: "abcdefghij", wantRest: "klmno",
+		},
+		{
+			name: "surrogate pair is not split", mode: model.ParseModePlain,
+			text: "😀😀😀😀😀😀", budget: 9,
+			wantPart: "😀😀😀😀", wantRest: "😀😀",
+		},
+		{
+			name: "surrogate pair after ascii", mode: model.ParseModePlain,
+			text: "a😀😀😀", budget: 4,
+			wantPart: "a😀", wantRest: "😀😀",
+		},
+		{
+			name: "markdown link is not cut", mode: model.ParseModeMarkdown,
+			text: "xx [a b c d](u) tail", budget: 10,
+			wantPart: "xx ", wantRest: "[a b c d](u) tail",
+		},
+		{
+			name: "markdown bold reopened", mode: model.ParseModeMarkdown,
+			text: "*bold text here*", budget: 10,
+			wantPart: "*bold *", wantRest: "*text here*",
+		},
+		{
+			name: "markdown v2 nested entities", mode: model.ParseModeMarkdownV2,
+			text: "*bold _it and_ more*", budget: 12,
+			wantPart: "*bold _it _*", wantRest: "*_and_ more*",
+		},
+		{
+			name: "markdown v2 spoiler and underline", mode: model.ParseModeMarkdownV2,
+			text: "||spoiler __under text__||", budget: 18,
+			wantPart: "||spoiler ||", wantRest: "||__under text__||",
+		},
+		{
+			name: "markdown v2 code ignores entities", mode: model.ParseModeMarkdownV2,
+			text: "`code * x` and more", budget: 12,
+			wantPart: "`code * x` ", wantRest: "and more",
+		},
+		{
+			name: "markdown v2 escape kept whole", mode: model.ParseModeMarkdownV2,
+			text: "a\\* b\\* c\\* d", budget: 6,
+			wantPart: "a\\* ", wantRest: "b\\* c\\* d",
+		},
+		{
+			name: "html nested tags reopened", mode: model.ParseModeHTML,
+			text: "<b>hello <i>big world</i></b>", budget: 22,
+			wantPart: "<b>hello </b>", wantRest: "<b><i>big world</i></b>",
+		},
+		{
+			name: "html entity is not split", mode: model.ParseModeHTML,
+			text: "a &amp; b", budget: 5,
+			wantPart: "a ", wantRest: "&amp; b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part, rest := cutPart(tt.text, tt.budget, markupFor(tt.mode).newState())
+			if part != tt.wantPart || rest != tt.wantRest {
+				t.Errorf("cutPart(%q, %d) = %q, %q, want %q, %q", tt.text, tt.budget, part, rest, tt.wantPart, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	// Лимит оставляет под текст каждой части 10 code units
+	const limit = partLabelReserve + 10
+
+	tests := []struct {
+		name string
+		mode model.ParseMode
+		text string
+		want []string
+	}{
+		{
+			name: "short message unchanged", mode: model.ParseModePlain,
+			text: "hello",
+			want: []string{"hello"},
+		},
+		{
+			name: "parts are labeled", mode: model.ParseModePlain,
+			text: "aaaa bbbb cccc dddd eeee ffff gggg hhhh iiii jjjj",
+			want: []string{
+				"aaaa bbbb \n(1/5)",
+				"cccc dddd \n(2/5)",
+				"eeee ffff \n(3/5)",
+				"gggg hhhh \n(4/5)",
+				"iiii jjjj\n(5/5)",
+			},
+		},
+		{
+			name: "surrogate pairs", mode: model.ParseModePlain,
+			text: strings.Repeat("😀", 24),
+			want: []string{
+				"😀😀😀😀😀\n(1/5)",
+				"😀😀😀😀😀\n(2/5)",
+				"😀😀😀😀😀\n(3/5)",
+				"😀😀😀😀😀\n(4/5)",
+				"😀😀😀😀\n(5/5)",
+			},
+		},
+		{
+			name: "markdown v2 label escaped", mode: model.ParseModeMarkdownV2,
+			text: "*bold _italic_ end* tail tail tail tail tail tail",
+			want: []string{
+				"*bold *\n\\(1/6\\)",
+				"*_italic_*\n\\(2/6\\)",
+				"* end* \n\\(3/6\\)",
+				"tail tail \n\\(4/6\\)",
+				"tail tail \n\\(5/6\\)",
+				"tail tail\n\\(6/6\\)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitMessage(tt.text, limit, markupFor(tt.mode))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitMessage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMessageFitsLimit(t *testing.T) {
+	const limit = partLabelReserve + 10
+
+	texts := map[model.ParseMode]string{
+		model.ParseModePlain:      strings.Repeat("строка 😀 text\n", 20),
+		model.ParseModeMarkdown:   strings.Repeat("*bold* _it_ `code` [link](http://example.com) ", 10),
+		model.ParseModeMarkdownV2: strings.Repeat("*b _i __u ||s|| u__ i_ b* ```\npre\n``` \\. ", 10),
+		// Вложенные теги, закрывающие маркеры которых не помещаются в часть вместе с текстом
+		model.ParseModeHTML: strings.Repeat("<b>hello <i>big <u>world</u></i></b> &amp; ", 10),
+	}
+
+	for mode, text := range texts {
+		t.Run(string(mode), func(t *testing.T) {
+			parts := splitMessage(text, limit, markupFor(mode))
+			if len(parts) < 2 {
+				t.Fatalf("splitMessage returned %d parts, want several", len(parts))
+			}
+			for i, part := range parts {
+				if n := utf16Len(part); n > limit {
+					t.Errorf("part %d has length %d, want at most %d: %q", i+1, n, limit, part)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadPart(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  model.ParseMode
+		text  string
+		limit int
+		want  string
+	}{
+		{
+			name: "short text unchanged", mode: model.ParseModePlain,
+			text: "hello", limit: 42,
+			want: "hello",
+		},
+		{
+			name: "cut with ellipsis", mode: model.ParseModePlain,
+			text: "aaaa bbbb cccc dddd eeee ffff gggg hhhh iiii jjjj", limit: 42,
+			want: "aaaa bbbb \n…",
+		},
+		{
+			name: "html tag closed", mode: model.ParseModeHTML,
+			text: "<b>aaaa bbbb cccc dddd eeee ffff gggg hhhh iiii jjjj</b>", limit: 46,
+			want: "<b>aaaa </b>\n…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headPart(tt.text, tt.limit, markupFor(tt.mode)); got != tt.want {
+				t.Errorf("headPart(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
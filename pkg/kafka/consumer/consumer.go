@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/pkg/errors"
@@ -14,10 +15,14 @@ type Logger interface {
 }
 
 type consumer struct {
-	group       sarama.ConsumerGroup
-	topics      []string
-	logger      *zap.Logger
-	middlewares []Middleware
+	group           sarama.ConsumerGroup
+	topics          []string
+	logger          *zap.Logger
+	middlewares     []Middleware
+	sessionListener SessionListener
+
+	topicResolver   TopicResolver
+	refreshInterval time.Duration
 }
 
 // NewConsumer — создаёт новый consumer.
@@ -30,12 +35,51 @@ func NewConsumer(group sarama.ConsumerGroup, topics []string, logger *zap.Logger
 	}
 }
 
+// SetSessionListener задаёт получателя уведомлений о начале и завершении сессии consumer group.
+func (c *consumer) SetSessionListener(listener SessionListener) {
+	c.sessionListener = listener
+}
+
+// SetTopicResolver задаёт динамический список топиков. Список перечитывается
+// с интервалом interval, и при его изменении consumer переподписывается.
+func (c *consumer) SetTopicResolver(resolver TopicResolver, interval time.Duration) {
+	c.topicResolver = resolver
+	c.refreshInterval = interval
+}
+
 // Consume запускает консьюмер для списка топиков.
 func (c *consumer) Consume(ctx context.Context, handler MessageHandler) error {
 	newGroupHandler := NewGroupHandler(handler, c.logger, c.middlewares...)
+	newGroupHandler.sessionListener = c.sessionListener
 
 	for {
-		if err := c.group.Consume(ctx, c.topics, newGroupHandler); err != nil {
+		topics, err := c.resolveTopics()
+		if err != nil || len(topics) == 0 {
+			if c.topicResolver == nil {
+				return errors.New("no Kafka topics to consume")
+			}
+			if err != nil {
+				c.logger.Error("Failed to resolve Kafka topics", zap.Error(err))
+			} else {
+				c.logger.Warn("No Kafka topics to consume, waiting for matching topics")
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.refreshInterval):
+				continue
+			}
+		}
+
+		sessionCtx, cancel := context.WithCancel(ctx)
+		if c.topicResolver != nil {
+			go c.watchTopics(sessionCtx, cancel, topics)
+		}
+
+		err = c.group.Consume(sessionCtx, topics, newGroupHandler)
+		cancel()
+		if err != nil {
 			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
 				return nil
 			}
@@ -0,0 +1,96 @@
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Заголовки, которые добавляются к сообщению при публикации в DLQ.
+const (
+	HeaderDLQError           = "x-dlq-error"
+	HeaderDLQSourceTopic     = "x-dlq-source-topic"
+	HeaderDLQSourcePartition = "x-dlq-source-partition"
+	HeaderDLQSourceOffset    = "x-dlq-source-offset"
+	HeaderDLQAttempts        = "x-dlq-attempts"
+	HeaderDLQTimestamp       = "x-dlq-timestamp"
+)
+
+// Задержки между попытками публикации в DLQ
+const (
+	deadLetterRetryInitial = time.Second
+	deadLetterRetryMax     = 30 * time.Second
+)
+
+// DeadLetterProducer — продюсер, публикующий сообщения в dead-letter topic.
+type DeadLetterProducer interface {
+	SendWithHeaders(ctx context.Context, key, value []byte, headers map[string][]byte) error
+}
+
+// NewDeadLetterMiddleware создаёт middleware, публикующий в DLQ сообщения,
+// обработка которых завершилась ошибкой. После успешной публикации ошибка
+// поглощается, чтобы сообщение было помечено и партиция не простаивала.
+// Неудачная публикация повторяется до успеха: иначе offset сообщения был бы
+// зафиксирован вместе со следующим и сообщение потерялось бы.
+// Если контекст сессии отменён, сообщение в DLQ не отправляется: оно будет
+// повторно доставлено после ребалансировки.
+func NewDeadLetterMiddleware(producer DeadLetterProducer, logger *zap.Logger) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return func(ctx context.Context, msg Message) error {
+			err := next(ctx, msg)
+			if err == nil || ctx.Err() != nil {
+				return err
+			}
+
+			headers := make(map[string][]byte, len(msg.Headers)+6)
+			for k, v := range msg.Headers {
+				headers[k] = v
+			}
+			headers[HeaderDLQError] = []byte(err.Error())
+			headers[HeaderDLQSourceTopic] = []byte(msg.Topic)
+			headers[HeaderDLQSourcePartition] = []byte(strconv.FormatInt(int64(msg.Partition), 10))
+			headers[HeaderDLQSourceOffset] = []byte(strconv.FormatInt(msg.Offset, 10))
+			headers[HeaderDLQAttempts] = []byte(strconv.Itoa(Attempts(err)))
+			headers[HeaderDLQTimestamp] = []byte(time.Now().UTC().Format(time.RFC3339Nano))
+
+			wait := deadLetterRetryInitial
+			for attempt := 1; ; attempt++ {
+				dlqErr := producer.SendWithHeaders(ctx, msg.Key, msg.Value, headers)
+				if dlqErr == nil {
+					break
+				}
+
+				logger.Error("Failed to publish message to dead-letter topic, retrying",
+					zap.String("topic", msg.Topic),
+					zap.Int32("partition", msg.Partition),
+					zap.Int64("offset", msg.Offset),
+					zap.Int("attempt", attempt),
+					zap.Duration("backoff", wait),
+					zap.NamedError("handler_error", err),
+					zap.Error(dlqErr),
+				)
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+				wait = min(2*wait, deadLetterRetryMax)
+			}
+
+			logger.Warn("Message moved to dead-letter topic",
+				zap.String("topic", msg.Topic),
+				zap.Int32("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+				zap.Int("attempts", Attempts(err)),
+				zap.Error(err),
+			)
+
+			return nil
+		}
+	}
+}
@@ -13,10 +13,14 @@ type MessageHandler func(ctx context.Context, msg Message) error
 // Middleware — функция middleware для дополнительной обработки.
 type Middleware func(next MessageHandler) MessageHandler
 
+// SessionListener — получает уведомления о начале (true) и завершении (false) сессии consumer group.
+type SessionListener func(active bool)
+
 // groupHandler — обёртка для sarama.ConsumerGroupHandler
 type groupHandler struct {
-	handler MessageHandler
-	logger  *zap.Logger
+	handler         MessageHandler
+	logger          *zap.Logger
+	sessionListener SessionListener
 }
 
 // NewGroupHandler создаёт новый groupHandler с middleware цепочкой.
@@ -33,10 +37,16 @@ func NewGroupHandler(handler MessageHandler, logger *zap.Logger, middlewares ...
 }
 
 func (g *groupHandler) Setup(sarama.ConsumerGroupSession) error {
+	if g.sessionListener != nil {
+		g.sessionListener(true)
+	}
 	return nil
 }
 
 func (g *groupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	if g.sessionListener != nil {
+		g.sessionListener(false)
+	}
 	return nil
 }
 
@@ -58,9 +68,27 @@ func (g *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim s
 				Timestamp:      message.Timestamp,
 				BlockTimestamp: message.BlockTimestamp,
 				Headers:        extractHeaders(message.Headers),
+				HighWaterMark:  claim.HighWaterMarkOffset(),
 			}
 
 			if err := g.handler(session.Context(), msg); err != nil {
+				// Сессия завершается: сообщение не помечается и будет доставлено повторно,
+				// иначе его offset зафиксировался бы вместе со следующим сообщением
+				if session.Context().Err() != nil {
+					g.logger.Info("Kafka session context done, message will be redelivered",
+						zap.String("topic", msg.Topic),
+						zap.Int32("partition", msg.Partition),
+						zap.Int64("offset", msg.Offset),
+					)
+					return nil
+				}
+
+				g.logger.Error("Failed to handle Kafka message, skipping",
+					zap.String("topic", msg.Topic),
+					zap.Int32("partition", msg.Partition),
+					zap.Int64("offset", msg.Offset),
+					zap.Error(err),
+				)
 				continue
 			}
 
@@ -13,4 +13,7 @@ type Message struct {
 	Topic     string
 	Partition int32
 	Offset    int64
+
+	// HighWaterMark — offset следующего сообщения, которое будет записано в партицию
+	HighWaterMark int64
 }
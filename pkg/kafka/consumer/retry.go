@@ -0,0 +1,164 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetryConfig — параметры повторной обработки сообщения.
+type RetryConfig struct {
+	MaxAttempts     int           // Максимальное количество попыток (включая первую)
+	InitialInterval time.Duration // Задержка перед первым повтором
+	MaxInterval     time.Duration // Верхняя граница задержки между попытками
+	Multiplier      float64       // Множитель экспоненциального роста задержки
+	Jitter          float64       // Доля случайного разброса задержки (0..1)
+	MaxElapsedTime  time.Duration // Максимальное суммарное время на все попытки (0 — без ограничения)
+}
+
+// DefaultRetryConfig возвращает параметры повторов по умолчанию.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:     5,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxElapsedTime:  2 * time.Minute,
+	}
+}
+
+// permanentError — ошибка, при которой повторять обработку бессмысленно.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent помечает ошибку как неустранимую: retry middleware не будет повторять обработку.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка как неустранимая.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// attemptsError — ошибка обработки с количеством выполненных попыток.
+type attemptsError struct {
+	attempts int
+	err      error
+}
+
+func (e *attemptsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attemptsError) Unwrap() error {
+	return e.err
+}
+
+// Attempts возвращает количество попыток обработки, сделанных до возникновения ошибки.
+// Для ошибок, не прошедших через retry middleware, возвращает 1.
+func Attempts(err error) int {
+	var a *attemptsError
+	if errors.As(err, &a) {
+		return a.attempts
+	}
+
+	return 1
+}
+
+// NewRetryMiddleware создаёт middleware, повторяющий обработку сообщения
+// с экспоненциальной задержкой и jitter. Повторы прекращаются при отмене
+// контекста сессии, чтобы не блокировать ребалансировку и остановку.
+func NewRetryMiddleware(cfg RetryConfig, logger *zap.Logger) Middleware {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = 1
+	}
+	if cfg.Jitter < 0 {
+		cfg.Jitter = 0
+	}
+	if cfg.Jitter > 1 {
+		cfg.Jitter = 1
+	}
+
+	return func(next MessageHandler) MessageHandler {
+		return func(ctx context.Context, msg Message) error {
+			start := time.Now()
+			interval := cfg.InitialInterval
+
+			var err error
+			for attempt := 1; ; attempt++ {
+				err = next(ctx, msg)
+				if err == nil {
+					return nil
+				}
+				if IsPermanent(err) || attempt >= cfg.MaxAttempts {
+					return &attemptsError{attempts: attempt, err: err}
+				}
+
+				wait := applyJitter(interval, cfg.Jitter)
+				if cfg.MaxElapsedTime > 0 && time.Since(start)+wait > cfg.MaxElapsedTime {
+					logger.Warn("Retry time budget exhausted",
+						zap.String("topic", msg.Topic),
+						zap.Int32("partition", msg.Partition),
+						zap.Int64("offset", msg.Offset),
+						zap.Int("attempt", attempt),
+						zap.Error(err),
+					)
+					return &attemptsError{attempts: attempt, err: err}
+				}
+
+				logger.Warn("Message handling failed, retrying",
+					zap.String("topic", msg.Topic),
+					zap.Int32("partition", msg.Partition),
+					zap.Int64("offset", msg.Offset),
+					zap.Int("attempt", attempt),
+					zap.Duration("backoff", wait),
+					zap.Error(err),
+				)
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return &attemptsError{attempts: attempt, err: err}
+				case <-timer.C:
+				}
+
+				interval = time.Duration(float64(interval) * cfg.Multiplier)
+				if cfg.MaxInterval > 0 && interval > cfg.MaxInterval {
+					interval = cfg.MaxInterval
+				}
+			}
+		}
+	}
+}
+
+func applyJitter(interval time.Duration, jitter float64) time.Duration {
+	if jitter == 0 || interval <= 0 {
+		return interval
+	}
+
+	delta := jitter * float64(interval)
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
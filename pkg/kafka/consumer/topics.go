@@ -0,0 +1,89 @@
+package consumer
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// TopicResolver возвращает актуальный список топиков для подписки
+type TopicResolver func() ([]string, error)
+
+// NewPatternTopicResolver возвращает список из явно заданных топиков и топиков
+// кластера, имена которых совпадают с одним из шаблонов. Служебные топики
+// (с префиксом "__") не включаются.
+func NewPatternTopicResolver(client sarama.Client, topics []string, patterns []*regexp.Regexp) TopicResolver {
+	return func() ([]string, error) {
+		if err := client.RefreshMetadata(); err != nil {
+			return nil, err
+		}
+
+		available, err := client.Topics()
+		if err != nil {
+			return nil, err
+		}
+
+		result := slices.Clone(topics)
+		for _, topic := range available {
+			if strings.HasPrefix(topic, "__") || slices.Contains(result, topic) {
+				continue
+			}
+			for _, pattern := range patterns {
+				if pattern.MatchString(topic) {
+					result = append(result, topic)
+					break
+				}
+			}
+		}
+
+		return result, nil
+	}
+}
+
+func (c *consumer) resolveTopics() ([]string, error) {
+	if c.topicResolver == nil {
+		return c.topics, nil
+	}
+
+	topics, err := c.topicResolver()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(topics)
+
+	return topics, nil
+}
+
+// watchTopics завершает сессию consumer group, когда меняется список топиков
+func (c *consumer) watchTopics(ctx context.Context, cancel context.CancelFunc, current []string) {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			topics, err := c.resolveTopics()
+			if err != nil {
+				c.logger.Error("Failed to refresh Kafka topics", zap.Error(err))
+				continue
+			}
+			if slices.Equal(topics, current) {
+				continue
+			}
+
+			c.logger.Info("Kafka topic list changed, resubscribing",
+				zap.Strings("old", current),
+				zap.Strings("new", topics),
+			)
+			cancel()
+			return
+		}
+	}
+}
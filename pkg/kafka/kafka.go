@@ -12,4 +12,5 @@ type Consumer interface {
 
 type Producer interface {
 	Send(ctx context.Context, key, value []byte) error
+	SendWithHeaders(ctx context.Context, key, value []byte, headers map[string][]byte) error
 }
@@ -27,11 +27,20 @@ func NewProducer(syncProducer sarama.SyncProducer, topic string, logger *zap.Log
 }
 
 func (p *producer) Send(ctx context.Context, key, value []byte) error {
-	partition, offset, err := p.syncProducer.SendMessage(&sarama.ProducerMessage{
+	return p.SendWithHeaders(ctx, key, value, nil)
+}
+
+func (p *producer) SendWithHeaders(ctx context.Context, key, value []byte, headers map[string][]byte) error {
+	msg := &sarama.ProducerMessage{
 		Topic: p.topic,
 		Key:   sarama.ByteEncoder(key),
 		Value: sarama.ByteEncoder(value),
-	})
+	}
+	for k, v := range headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: v})
+	}
+
+	partition, offset, err := p.syncProducer.SendMessage(msg)
 	if err != nil {
 		p.logger.Error("Failed to send message", zap.Error(err))
 		return err
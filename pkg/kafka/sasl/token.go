@@ -0,0 +1,48 @@
+package sasl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// staticTokenProvider возвращает один и тот же токен OAUTHBEARER
+type staticTokenProvider struct {
+	token string
+}
+
+// NewStaticTokenProvider возвращает провайдер неизменяемого токена OAUTHBEARER
+func NewStaticTokenProvider(token string) sarama.AccessTokenProvider {
+	return &staticTokenProvider{token: token}
+}
+
+func (p *staticTokenProvider) Token() (*sarama.AccessToken, error) {
+	return &sarama.AccessToken{Token: p.token}, nil
+}
+
+// fileTokenProvider читает токен OAUTHBEARER из файла при каждом подключении,
+// поэтому обновлённый внешней системой токен подхватывается без перезапуска
+type fileTokenProvider struct {
+	path string
+}
+
+// NewFileTokenProvider возвращает провайдер токена OAUTHBEARER из файла
+func NewFileTokenProvider(path string) sarama.AccessTokenProvider {
+	return &fileTokenProvider{path: path}
+}
+
+func (p *fileTokenProvider) Token() (*sarama.AccessToken, error) {
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oauth token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, fmt.Errorf("oauth token file %s is empty", p.path)
+	}
+
+	return &sarama.AccessToken{Token: token}, nil
+}